@@ -0,0 +1,70 @@
+package structures
+
+import (
+	"fmt"
+	"hash/maphash"
+)
+
+// Hasher produces a shard-selection hash for a key of type K. It only needs
+// to distribute keys evenly; it is not used for equality checks.
+type Hasher[K comparable] func(key K) uint64
+
+var hashSeed = maphash.MakeSeed()
+
+// defaultHasher returns a Hasher with fast paths for strings and the builtin
+// integer types, falling back to hashing the key's string representation for
+// every other comparable type.
+func defaultHasher[K comparable]() Hasher[K] {
+	return func(key K) uint64 {
+		switch k := any(key).(type) {
+		case string:
+			return hashBytes(k)
+		case int:
+			return uint64(k)
+		case int8:
+			return uint64(k)
+		case int16:
+			return uint64(k)
+		case int32:
+			return uint64(k)
+		case int64:
+			return uint64(k)
+		case uint:
+			return uint64(k)
+		case uint8:
+			return uint64(k)
+		case uint16:
+			return uint64(k)
+		case uint32:
+			return uint64(k)
+		case uint64:
+			return k
+		case uintptr:
+			return uint64(k)
+		default:
+			return hashBytes(fmt.Sprintf("%v", k))
+		}
+	}
+}
+
+// hashBytes hashes s with a process-wide seed so shard assignment is stable
+// for the lifetime of the program but not predictable across runs.
+func hashBytes(s string) uint64 {
+	var h maphash.Hash
+	h.SetSeed(hashSeed)
+	_, _ = h.WriteString(s)
+	return h.Sum64()
+}
+
+// nextPowerOfTwo rounds n up to the next power of two so that shard
+// selection can be done with a bitmask instead of a modulo.
+func nextPowerOfTwo(n int) int {
+	if n <= 1 {
+		return 1
+	}
+	p := 1
+	for p < n {
+		p <<= 1
+	}
+	return p
+}
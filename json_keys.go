@@ -0,0 +1,55 @@
+package structures
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+// useObjectForm reports whether K is a string or integer type, in which
+// case Map/Table JSON encoding uses a plain JSON object keyed by the
+// stringified key instead of an array of key/value pairs. Floating-point
+// and complex key types are not included and use the array form.
+func useObjectForm[K comparable]() bool {
+	var zero K
+	t := reflect.TypeOf(zero)
+	if t == nil {
+		return false
+	}
+
+	switch t.Kind() {
+	case reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return true
+	default:
+		return false
+	}
+}
+
+// parseJSONKey parses s back into a key of type K. It only supports the
+// string and integer kinds accepted by useObjectForm.
+func parseJSONKey[K comparable](s string) (key K, err error) {
+	rv := reflect.ValueOf(&key).Elem()
+
+	switch rv.Kind() {
+	case reflect.String:
+		rv.SetString(s)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return key, err
+		}
+		rv.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		n, err := strconv.ParseUint(s, 10, 64)
+		if err != nil {
+			return key, err
+		}
+		rv.SetUint(n)
+	default:
+		return key, fmt.Errorf("structures: unsupported JSON object key type %s", rv.Kind())
+	}
+
+	return key, nil
+}
@@ -25,4 +25,48 @@ type Map[K comparable, V any] interface {
 	Set(key K, val V) error
 	// ToMap converts the map instance to a native map
 	ToMap() map[K]V
+	// Range iterates over every key/value pair, invoking fn for each of them.
+	// Iteration stops early if fn returns false.
+	Range(fn func(key K, val V) bool)
+	// Keys returns all keys currently visible in the map
+	Keys() []K
+	// Values returns all values currently visible in the map
+	Values() []V
+	// Len returns the number of entries currently visible in the map
+	Len() int
+	// Compute atomically computes a new value for key under the write lock.
+	// fn receives the current value and whether it was found (in the local
+	// table or, failing that, the shadow) and returns the value to store and
+	// whether the key should be deleted instead. actual is the value fn
+	// returned; ok is false if the key was deleted.
+	Compute(key K, fn func(oldVal V, loaded bool) (newVal V, del bool)) (actual V, ok bool)
+	// LoadOrCompute returns the existing value for key if one is visible,
+	// otherwise it stores and returns the result of fn. loaded is true if an
+	// existing value was returned instead of fn's result.
+	LoadOrCompute(key K, fn func() V) (value V, loaded bool)
+	// Swap stores val as the new value for key and returns the value it
+	// replaced, if any.
+	Swap(key K, val V) (previous V, loaded bool)
+}
+
+// ShadowedMap extends Map with the copy-on-write overlay operations
+// supported by maps constructed with NewMap: ShadowCopy, Commit, Discard and
+// Flatten.
+type ShadowedMap[K comparable, V any] interface {
+	Map[K, V]
+	// ShadowCopy returns a new map that reads through to this map for any
+	// key not overridden locally.
+	ShadowCopy() ShadowedMap[K, V]
+	// Commit merges this map's local writes and deletes into its shadow,
+	// then clears the local layer. It holds this map's write lock
+	// throughout, but each write into the shadow takes the shadow's lock on
+	// its own, so a reader of the shadow can see a partly applied commit.
+	// It errors if this map has no shadow to commit into.
+	Commit() error
+	// Discard empties the local layer, undoing every write and delete made
+	// since the last Commit, without touching the shadow.
+	Discard()
+	// Flatten returns a new, shadow-free map materializing the full view
+	// currently visible through this map.
+	Flatten() Map[K, V]
 }
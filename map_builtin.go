@@ -7,8 +7,9 @@ import (
 
 // Map is a wrapper for a 2 dimensional map that manages its contents
 type builtinMap[K comparable, V any] struct {
-	table  map[K]V
-	shadow Map[K, V]
+	table      map[K]V
+	tombstones map[K]struct{}
+	shadow     Map[K, V]
 	sync.RWMutex
 }
 
@@ -20,6 +21,12 @@ func (t *builtinMap[K, V]) Add(key K, newVal V) error {
 	if _, exists := t.table[key]; exists {
 		return TableDuplicateKeys
 	}
+
+	if _, tombstoned := t.tombstones[key]; !tombstoned && t.shadow != nil && t.shadow.Contains(key) {
+		return TableDuplicateKeys
+	}
+
+	delete(t.tombstones, key)
 	t.table[key] = newVal
 	return nil
 }
@@ -29,6 +36,10 @@ func (t *builtinMap[K, V]) Contains(key K) bool {
 	t.RLock()
 	defer t.RUnlock()
 
+	if _, tombstoned := t.tombstones[key]; tombstoned {
+		return false
+	}
+
 	if _, exists := t.table[key]; exists {
 		return true
 	}
@@ -39,18 +50,25 @@ func (t *builtinMap[K, V]) Contains(key K) bool {
 	return false
 }
 
-// Delete deletes the value by its keys, if the keys does not exist an error will be returned.
+// Delete deletes the value by its keys, if the keys does not exist an error
+// will be returned. Deleting a key that only exists in the shadow records a
+// tombstone locally instead of touching the shadow, so the key disappears
+// from this map's view until Commit or Discard is called.
 func (t *builtinMap[K, V]) Delete(key K) error {
 	t.Lock()
 	defer t.Unlock()
 
 	if _, exists := t.table[key]; exists {
 		delete(t.table, key)
+		if t.shadow != nil {
+			t.tombstones[key] = struct{}{}
+		}
 		return nil
 	}
 
-	if t.shadow != nil && t.shadow.Contains(key) {
-		return errors.New("cannot delete from shadow table")
+	if _, tombstoned := t.tombstones[key]; !tombstoned && t.shadow != nil && t.shadow.Contains(key) {
+		t.tombstones[key] = struct{}{}
+		return nil
 	}
 
 	return TableKeysNotFound
@@ -61,6 +79,10 @@ func (t *builtinMap[K, V]) Get(key K) (value V, err error) {
 	t.RLock()
 	defer t.RUnlock()
 
+	if _, tombstoned := t.tombstones[key]; tombstoned {
+		return value, TableKeysNotFound
+	}
+
 	err = TableKeysNotFound
 	if val, exists := t.table[key]; exists {
 		value = val
@@ -79,6 +101,10 @@ func (t *builtinMap[K, V]) GetOrDefault(key K, def V) (value V) {
 	t.RLock()
 	defer t.RUnlock()
 
+	if _, tombstoned := t.tombstones[key]; tombstoned {
+		return def
+	}
+
 	if val, exists := t.table[key]; exists {
 		return val
 	}
@@ -95,17 +121,22 @@ func (t *builtinMap[K, V]) GetOrSet(key K, newVal V) (value V) {
 	t.Lock()
 	defer t.Unlock()
 
-	if v, exists := t.table[key]; exists {
-		value = v
-	} else if t.shadow != nil {
-		if v, err := t.shadow.Get(key); err == nil {
-			value = v
+	_, tombstoned := t.tombstones[key]
+
+	if !tombstoned {
+		if v, exists := t.table[key]; exists {
+			return v
+		}
+		if t.shadow != nil {
+			if v, err := t.shadow.Get(key); err == nil {
+				return v
+			}
 		}
-	} else {
-		t.table[key] = newVal
-		value = newVal
 	}
-	return
+
+	delete(t.tombstones, key)
+	t.table[key] = newVal
+	return newVal
 }
 
 // Set stores val as a new value by key
@@ -113,17 +144,136 @@ func (t *builtinMap[K, V]) Set(key K, newVal V) error {
 	t.Lock()
 	defer t.Unlock()
 
+	delete(t.tombstones, key)
 	t.table[key] = newVal
 	return nil
 }
 
+// Range iterates over every key/value pair, invoking fn for each of them.
+// Iteration stops early if fn returns false. Keys already visited in the
+// local table are not visited again when iterating the shadow.
+func (t *builtinMap[K, V]) Range(fn func(key K, val V) bool) {
+	t.RLock()
+	defer t.RUnlock()
+
+	for k, v := range t.table {
+		if !fn(k, v) {
+			return
+		}
+	}
+
+	if t.shadow != nil {
+		t.shadow.Range(func(key K, val V) bool {
+			if _, exists := t.table[key]; exists {
+				return true
+			}
+			if _, tombstoned := t.tombstones[key]; tombstoned {
+				return true
+			}
+			return fn(key, val)
+		})
+	}
+}
+
+// Keys returns all keys currently visible in the map
+func (t *builtinMap[K, V]) Keys() []K {
+	keys := make([]K, 0, t.Len())
+	t.Range(func(key K, _ V) bool {
+		keys = append(keys, key)
+		return true
+	})
+	return keys
+}
+
+// Values returns all values currently visible in the map
+func (t *builtinMap[K, V]) Values() []V {
+	values := make([]V, 0, t.Len())
+	t.Range(func(_ K, val V) bool {
+		values = append(values, val)
+		return true
+	})
+	return values
+}
+
+// Len returns the number of entries currently visible in the map
+func (t *builtinMap[K, V]) Len() int {
+	t.RLock()
+	defer t.RUnlock()
+
+	count := len(t.table)
+	if t.shadow != nil {
+		t.shadow.Range(func(key K, _ V) bool {
+			if _, exists := t.table[key]; exists {
+				return true
+			}
+			if _, tombstoned := t.tombstones[key]; tombstoned {
+				return true
+			}
+			count++
+			return true
+		})
+	}
+	return count
+}
+
 // ShadowCopy returns a new map with the current map as its shadow
-func (t *builtinMap[K, V]) ShadowCopy() Map[K, V] {
+func (t *builtinMap[K, V]) ShadowCopy() ShadowedMap[K, V] {
 	newMap := NewMap[K, V]()
 	newMap.(*builtinMap[K, V]).shadow = t
 	return newMap
 }
 
+// Commit merges this map's local writes and deletes into its shadow: stored
+// values are written through via Set and tombstoned keys are deleted, then
+// the local layer is cleared. This map's write lock is held throughout, but
+// each Set or Delete on the shadow locks the shadow separately, so readers
+// of the shadow can observe a partly applied commit. It errors if this map
+// has no shadow to commit into.
+func (t *builtinMap[K, V]) Commit() error {
+	t.Lock()
+	defer t.Unlock()
+
+	if t.shadow == nil {
+		return errors.New("map has no shadow to commit into")
+	}
+
+	for key := range t.tombstones {
+		if err := t.shadow.Delete(key); err != nil && err != TableKeysNotFound {
+			return err
+		}
+	}
+
+	for k, v := range t.table {
+		if err := t.shadow.Set(k, v); err != nil {
+			return err
+		}
+	}
+
+	t.table = make(map[K]V)
+	t.tombstones = make(map[K]struct{})
+	return nil
+}
+
+// Discard empties the local layer, undoing every write and delete made
+// since the last Commit, without touching the shadow.
+func (t *builtinMap[K, V]) Discard() {
+	t.Lock()
+	defer t.Unlock()
+
+	t.table = make(map[K]V)
+	t.tombstones = make(map[K]struct{})
+}
+
+// Flatten returns a new, shadow-free map materializing the full view
+// currently visible through this map.
+func (t *builtinMap[K, V]) Flatten() Map[K, V] {
+	flat := NewMap[K, V]()
+	for k, v := range t.ToMap() {
+		_ = flat.Set(k, v)
+	}
+	return flat
+}
+
 // ToMap converts the map instance to a native map
 func (t *builtinMap[K, V]) ToMap() map[K]V {
 	t.RLock()
@@ -134,20 +284,25 @@ func (t *builtinMap[K, V]) ToMap() map[K]V {
 	}
 
 	m := make(map[K]V)
-	for k, v := range t.table {
+	for k, v := range t.shadow.ToMap() {
+		if _, tombstoned := t.tombstones[k]; tombstoned {
+			continue
+		}
 		m[k] = v
 	}
 
-	for k, v := range t.shadow.ToMap() {
+	for k, v := range t.table {
 		m[k] = v
 	}
 	return m
 }
 
-// NewMap will create a new, empty instance of Map
-func NewMap[K comparable, V any]() Map[K, V] {
+// NewMap will create a new, empty instance of Map, with support for
+// copy-on-write shadow overlays via ShadowedMap
+func NewMap[K comparable, V any]() ShadowedMap[K, V] {
 	return &builtinMap[K, V]{
-		table:   make(map[K]V),
-		RWMutex: sync.RWMutex{},
+		table:      make(map[K]V),
+		tombstones: make(map[K]struct{}),
+		RWMutex:    sync.RWMutex{},
 	}
 }
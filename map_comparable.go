@@ -0,0 +1,73 @@
+package structures
+
+// ComparableMap extends Map with atomic compare-and-swap/compare-and-delete
+// operations. These require value equality, so they are only available for
+// maps constructed with NewComparableMap.
+type ComparableMap[K comparable, V comparable] interface {
+	Map[K, V]
+	// CompareAndSwap stores newVal for key if and only if the value
+	// currently visible for key equals old, returning whether the swap
+	// happened.
+	CompareAndSwap(key K, old, newVal V) bool
+	// CompareAndDelete deletes key if and only if the value currently
+	// visible for key equals old, returning whether the delete happened.
+	CompareAndDelete(key K, old V) bool
+}
+
+// comparableMap adds CompareAndSwap/CompareAndDelete on top of builtinMap
+type comparableMap[K comparable, V comparable] struct {
+	*builtinMap[K, V]
+}
+
+// NewComparableMap will create a new, empty instance of ComparableMap
+func NewComparableMap[K comparable, V comparable]() ComparableMap[K, V] {
+	return &comparableMap[K, V]{builtinMap: NewMap[K, V]().(*builtinMap[K, V])}
+}
+
+// CompareAndSwap stores newVal for key if and only if the value currently
+// visible for key equals old, returning whether the swap happened.
+func (m *comparableMap[K, V]) CompareAndSwap(key K, old, newVal V) bool {
+	m.Lock()
+	defer m.Unlock()
+
+	current, exists := m.table[key]
+	_, tombstoned := m.tombstones[key]
+	if !exists && !tombstoned && m.shadow != nil {
+		if v, err := m.shadow.Get(key); err == nil {
+			current, exists = v, true
+		}
+	}
+
+	if !exists || current != old {
+		return false
+	}
+
+	delete(m.tombstones, key)
+	m.table[key] = newVal
+	return true
+}
+
+// CompareAndDelete deletes key if and only if the value currently visible
+// for key equals old, returning whether the delete happened.
+func (m *comparableMap[K, V]) CompareAndDelete(key K, old V) bool {
+	m.Lock()
+	defer m.Unlock()
+
+	current, exists := m.table[key]
+	_, tombstoned := m.tombstones[key]
+	if !exists && !tombstoned && m.shadow != nil {
+		if v, err := m.shadow.Get(key); err == nil {
+			current, exists = v, true
+		}
+	}
+
+	if !exists || current != old {
+		return false
+	}
+
+	delete(m.table, key)
+	if m.shadow != nil {
+		m.tombstones[key] = struct{}{}
+	}
+	return true
+}
@@ -0,0 +1,74 @@
+package structures
+
+// Compute atomically computes a new value for key under the write lock. If
+// key is only visible through the shadow, the computed value is written
+// copy-on-write into the local table, leaving the shadow untouched.
+func (t *builtinMap[K, V]) Compute(key K, fn func(oldVal V, loaded bool) (newVal V, del bool)) (actual V, ok bool) {
+	t.Lock()
+	defer t.Unlock()
+
+	oldVal, loaded := t.table[key]
+	_, tombstoned := t.tombstones[key]
+	if !loaded && !tombstoned && t.shadow != nil {
+		if v, err := t.shadow.Get(key); err == nil {
+			oldVal = v
+			loaded = true
+		}
+	}
+
+	newVal, del := fn(oldVal, loaded)
+	if del {
+		delete(t.table, key)
+		if t.shadow != nil {
+			t.tombstones[key] = struct{}{}
+		}
+		return newVal, false
+	}
+
+	delete(t.tombstones, key)
+	t.table[key] = newVal
+	return newVal, true
+}
+
+// LoadOrCompute returns the existing value for key if one is visible,
+// otherwise it stores and returns the result of fn.
+func (t *builtinMap[K, V]) LoadOrCompute(key K, fn func() V) (value V, loaded bool) {
+	t.Lock()
+	defer t.Unlock()
+
+	if v, exists := t.table[key]; exists {
+		return v, true
+	}
+
+	_, tombstoned := t.tombstones[key]
+	if !tombstoned && t.shadow != nil {
+		if v, err := t.shadow.Get(key); err == nil {
+			return v, true
+		}
+	}
+
+	value = fn()
+	delete(t.tombstones, key)
+	t.table[key] = value
+	return value, false
+}
+
+// Swap stores val as the new value for key and returns the value it
+// replaced, if any.
+func (t *builtinMap[K, V]) Swap(key K, val V) (previous V, loaded bool) {
+	t.Lock()
+	defer t.Unlock()
+
+	_, tombstoned := t.tombstones[key]
+	if v, exists := t.table[key]; exists {
+		previous, loaded = v, true
+	} else if !tombstoned && t.shadow != nil {
+		if v, err := t.shadow.Get(key); err == nil {
+			previous, loaded = v, true
+		}
+	}
+
+	delete(t.tombstones, key)
+	t.table[key] = val
+	return
+}
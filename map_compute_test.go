@@ -0,0 +1,161 @@
+package structures
+
+import "testing"
+
+func TestMapCompute(t *testing.T) {
+	m := NewMap[string, int]()
+
+	actual, ok := m.Compute("a", func(oldVal int, loaded bool) (int, bool) {
+		if loaded {
+			t.Fatalf("Compute on a missing key should report loaded = false")
+		}
+		return 1, false
+	})
+	if !ok || actual != 1 {
+		t.Fatalf("Compute(a) = %d, %v, want 1, true", actual, ok)
+	}
+
+	actual, ok = m.Compute("a", func(oldVal int, loaded bool) (int, bool) {
+		if !loaded || oldVal != 1 {
+			t.Fatalf("Compute should see the previously stored value, got oldVal=%d loaded=%v", oldVal, loaded)
+		}
+		return 0, true
+	})
+	if ok {
+		t.Fatalf("Compute requesting delete should report ok = false")
+	}
+	if m.Contains("a") {
+		t.Fatalf("key should be gone after Compute requested a delete")
+	}
+}
+
+func TestMapLoadOrCompute(t *testing.T) {
+	m := NewMap[string, int]()
+
+	value, loaded := m.LoadOrCompute("a", func() int { return 42 })
+	if loaded || value != 42 {
+		t.Fatalf("LoadOrCompute(a) = %d, %v, want 42, false", value, loaded)
+	}
+
+	value, loaded = m.LoadOrCompute("a", func() int {
+		t.Fatalf("fn should not be called when a value already exists")
+		return 0
+	})
+	if !loaded || value != 42 {
+		t.Fatalf("LoadOrCompute(a) = %d, %v, want 42, true", value, loaded)
+	}
+}
+
+func TestMapSwap(t *testing.T) {
+	m := NewMap[string, int]()
+
+	previous, loaded := m.Swap("a", 1)
+	if loaded {
+		t.Fatalf("Swap on a missing key should report loaded = false, got previous=%d", previous)
+	}
+
+	previous, loaded = m.Swap("a", 2)
+	if !loaded || previous != 1 {
+		t.Fatalf("Swap(a, 2) = %d, %v, want 1, true", previous, loaded)
+	}
+	if v, _ := m.Get("a"); v != 2 {
+		t.Fatalf("Get(a) after Swap = %d, want 2", v)
+	}
+}
+
+func TestMapShadowCopyCompute(t *testing.T) {
+	base := NewMap[string, int]()
+	_ = base.Set("a", 1)
+
+	overlay := base.ShadowCopy()
+	actual, ok := overlay.Compute("a", func(oldVal int, loaded bool) (int, bool) {
+		if !loaded || oldVal != 1 {
+			t.Fatalf("Compute should see the shadow's value, got oldVal=%d loaded=%v", oldVal, loaded)
+		}
+		return 10, false
+	})
+	if !ok || actual != 10 {
+		t.Fatalf("Compute(a) = %d, %v, want 10, true", actual, ok)
+	}
+
+	if v, _ := overlay.Get("a"); v != 10 {
+		t.Fatalf("overlay.Get(a) = %d, want 10", v)
+	}
+	if v, _ := base.Get("a"); v != 1 {
+		t.Fatalf("base should be unaffected by a Compute against the overlay, got a=%d", v)
+	}
+}
+
+func TestMapShadowCopySwap(t *testing.T) {
+	base := NewMap[string, int]()
+	_ = base.Set("a", 1)
+
+	overlay := base.ShadowCopy()
+	previous, loaded := overlay.Swap("a", 10)
+	if !loaded || previous != 1 {
+		t.Fatalf("Swap(a, 10) = %d, %v, want 1, true (swap must see the shadow's value)", previous, loaded)
+	}
+
+	if v, _ := overlay.Get("a"); v != 10 {
+		t.Fatalf("overlay.Get(a) = %d, want 10", v)
+	}
+	if v, _ := base.Get("a"); v != 1 {
+		t.Fatalf("base should be unaffected by a Swap against the overlay, got a=%d", v)
+	}
+}
+
+func TestMapShadowCopyLoadOrCompute(t *testing.T) {
+	base := NewMap[string, int]()
+	_ = base.Set("a", 1)
+
+	overlay := base.ShadowCopy()
+	value, loaded := overlay.LoadOrCompute("a", func() int {
+		t.Fatalf("fn should not be called when a shadowed value already exists")
+		return 0
+	})
+	if !loaded || value != 1 {
+		t.Fatalf("LoadOrCompute(a) = %d, %v, want 1, true (must see the shadow's value)", value, loaded)
+	}
+
+	value, loaded = overlay.LoadOrCompute("b", func() int { return 2 })
+	if loaded || value != 2 {
+		t.Fatalf("LoadOrCompute(b) = %d, %v, want 2, false", value, loaded)
+	}
+
+	if v, _ := overlay.Get("b"); v != 2 {
+		t.Fatalf("overlay.Get(b) = %d, want 2", v)
+	}
+	if base.Contains("b") {
+		t.Fatalf("base should be unaffected by a LoadOrCompute miss against the overlay")
+	}
+}
+
+func TestComparableMapCompareAndSwap(t *testing.T) {
+	m := NewComparableMap[string, int]()
+	_ = m.Set("a", 1)
+
+	if m.CompareAndSwap("a", 2, 3) {
+		t.Fatalf("CompareAndSwap should fail when old does not match the current value")
+	}
+	if !m.CompareAndSwap("a", 1, 3) {
+		t.Fatalf("CompareAndSwap should succeed when old matches the current value")
+	}
+	if v, _ := m.Get("a"); v != 3 {
+		t.Fatalf("Get(a) after CompareAndSwap = %d, want 3", v)
+	}
+}
+
+func TestComparableMapCompareAndDelete(t *testing.T) {
+	m := NewComparableMap[string, int]()
+	_ = m.Set("a", 1)
+
+	if m.CompareAndDelete("a", 2) {
+		t.Fatalf("CompareAndDelete should fail when old does not match the current value")
+	}
+	if !m.CompareAndDelete("a", 1) {
+		t.Fatalf("CompareAndDelete should succeed when old matches the current value")
+	}
+	if m.Contains("a") {
+		t.Fatalf("key should be gone after a successful CompareAndDelete")
+	}
+}
@@ -0,0 +1,46 @@
+package structures
+
+import "testing"
+
+func TestMapRangeKeysValuesLen(t *testing.T) {
+	m := NewMap[string, int]()
+	_ = m.Set("a", 1)
+	_ = m.Set("b", 2)
+	_ = m.Set("c", 3)
+
+	if m.Len() != 3 {
+		t.Fatalf("Len() = %d, want 3", m.Len())
+	}
+
+	seen := make(map[string]int)
+	m.Range(func(key string, val int) bool {
+		seen[key] = val
+		return true
+	})
+	if len(seen) != 3 || seen["a"] != 1 || seen["b"] != 2 || seen["c"] != 3 {
+		t.Fatalf("Range visited %v, want {a:1 b:2 c:3}", seen)
+	}
+
+	if len(m.Keys()) != 3 {
+		t.Fatalf("Keys() = %v, want 3 entries", m.Keys())
+	}
+	if len(m.Values()) != 3 {
+		t.Fatalf("Values() = %v, want 3 entries", m.Values())
+	}
+}
+
+func TestMapRangeStopsEarly(t *testing.T) {
+	m := NewMap[string, int]()
+	_ = m.Set("a", 1)
+	_ = m.Set("b", 2)
+	_ = m.Set("c", 3)
+
+	visited := 0
+	m.Range(func(key string, val int) bool {
+		visited++
+		return false
+	})
+	if visited != 1 {
+		t.Fatalf("Range visited %d entries, want 1 after returning false", visited)
+	}
+}
@@ -0,0 +1,332 @@
+package structures
+
+import "sync"
+
+// LRUMap extends Map with a Peek method that reads a value without
+// affecting its recency, for maps constructed with NewLRUMap.
+type LRUMap[K comparable, V any] interface {
+	Map[K, V]
+	// Peek returns the value by key without updating its recency. If key
+	// does not exist an error will be returned.
+	Peek(key K) (V, error)
+}
+
+// lruNode is an entry in the doubly-linked recency list, most-recently-used first
+type lruNode[K comparable, V any] struct {
+	key        K
+	value      V
+	prev, next *lruNode[K, V]
+}
+
+// lruMap is a bounded Map that evicts the least-recently-used entry once
+// its size exceeds capacity. A doubly-linked list tracks recency; a plain
+// Mutex guards it because reads mutate the list just as writes do, so an
+// RWMutex would buy nothing.
+type lruMap[K comparable, V any] struct {
+	capacity int
+	items    map[K]*lruNode[K, V]
+	head     *lruNode[K, V]
+	tail     *lruNode[K, V]
+	onEvict  func(K, V)
+	sync.Mutex
+}
+
+// NewLRUMap creates a new, empty Map that holds at most capacity entries,
+// evicting the least-recently-used entry on overflow. If onEvict is given,
+// it is invoked with the key and value of every entry evicted this way.
+func NewLRUMap[K comparable, V any](capacity int, onEvict ...func(key K, val V)) LRUMap[K, V] {
+	m := &lruMap[K, V]{
+		capacity: capacity,
+		items:    make(map[K]*lruNode[K, V]),
+	}
+	if len(onEvict) > 0 {
+		m.onEvict = onEvict[0]
+	}
+	return m
+}
+
+// unlinkLocked removes n from the recency list. The caller must hold the lock.
+func (m *lruMap[K, V]) unlinkLocked(n *lruNode[K, V]) {
+	if n.prev != nil {
+		n.prev.next = n.next
+	} else {
+		m.head = n.next
+	}
+	if n.next != nil {
+		n.next.prev = n.prev
+	} else {
+		m.tail = n.prev
+	}
+	n.prev, n.next = nil, nil
+}
+
+// pushFrontLocked inserts n as the most-recently-used entry. The caller must hold the lock.
+func (m *lruMap[K, V]) pushFrontLocked(n *lruNode[K, V]) {
+	n.prev = nil
+	n.next = m.head
+	if m.head != nil {
+		m.head.prev = n
+	}
+	m.head = n
+	if m.tail == nil {
+		m.tail = n
+	}
+}
+
+// touchLocked marks n as the most-recently-used entry. The caller must hold the lock.
+func (m *lruMap[K, V]) touchLocked(n *lruNode[K, V]) {
+	if m.head == n {
+		return
+	}
+	m.unlinkLocked(n)
+	m.pushFrontLocked(n)
+}
+
+// insertLocked adds a brand new entry and evicts as needed. The caller must hold the lock.
+func (m *lruMap[K, V]) insertLocked(key K, val V) *lruNode[K, V] {
+	n := &lruNode[K, V]{key: key, value: val}
+	m.items[key] = n
+	m.pushFrontLocked(n)
+	m.evictIfNeededLocked()
+	return n
+}
+
+// evictIfNeededLocked evicts least-recently-used entries until the map is
+// within capacity. The caller must hold the lock.
+func (m *lruMap[K, V]) evictIfNeededLocked() {
+	for len(m.items) > m.capacity {
+		tail := m.tail
+		if tail == nil {
+			return
+		}
+		m.unlinkLocked(tail)
+		delete(m.items, tail.key)
+		if m.onEvict != nil {
+			m.onEvict(tail.key, tail.value)
+		}
+	}
+}
+
+// Add stores a new value by the given key or will error if the key already exists
+func (m *lruMap[K, V]) Add(key K, newVal V) error {
+	m.Lock()
+	defer m.Unlock()
+
+	if _, exists := m.items[key]; exists {
+		return MapDuplicateKey
+	}
+
+	m.insertLocked(key, newVal)
+	return nil
+}
+
+// Contains returns a whether the given key is contained in the map or not.
+func (m *lruMap[K, V]) Contains(key K) bool {
+	m.Lock()
+	defer m.Unlock()
+
+	_, exists := m.items[key]
+	return exists
+}
+
+// Delete deletes a value by key, if key does not exist an error will be returned.
+func (m *lruMap[K, V]) Delete(key K) error {
+	m.Lock()
+	defer m.Unlock()
+
+	n, exists := m.items[key]
+	if !exists {
+		return MapKeyNotFound
+	}
+
+	m.unlinkLocked(n)
+	delete(m.items, key)
+	return nil
+}
+
+// Get returns the value by key and marks it most-recently-used, if key does
+// not exist an error will be returned.
+func (m *lruMap[K, V]) Get(key K) (value V, err error) {
+	m.Lock()
+	defer m.Unlock()
+
+	n, exists := m.items[key]
+	if !exists {
+		return value, MapKeyNotFound
+	}
+
+	m.touchLocked(n)
+	return n.value, nil
+}
+
+// GetOrDefault returns the value by key and marks it most-recently-used, if
+// key does not exist def will be returned.
+func (m *lruMap[K, V]) GetOrDefault(key K, def V) V {
+	m.Lock()
+	defer m.Unlock()
+
+	if n, exists := m.items[key]; exists {
+		m.touchLocked(n)
+		return n.value
+	}
+	return def
+}
+
+// GetOrSet returns the value by key, if key does not exist def will be
+// stored and returned instead. Either way the key becomes most-recently-used.
+func (m *lruMap[K, V]) GetOrSet(key K, def V) V {
+	m.Lock()
+	defer m.Unlock()
+
+	if n, exists := m.items[key]; exists {
+		m.touchLocked(n)
+		return n.value
+	}
+
+	return m.insertLocked(key, def).value
+}
+
+// Set stores val as a new value by key, marking it most-recently-used.
+func (m *lruMap[K, V]) Set(key K, newVal V) error {
+	m.Lock()
+	defer m.Unlock()
+
+	if n, exists := m.items[key]; exists {
+		n.value = newVal
+		m.touchLocked(n)
+		return nil
+	}
+
+	m.insertLocked(key, newVal)
+	return nil
+}
+
+// Peek returns the value by key without updating its recency, if key does
+// not exist an error will be returned.
+func (m *lruMap[K, V]) Peek(key K) (value V, err error) {
+	m.Lock()
+	defer m.Unlock()
+
+	n, exists := m.items[key]
+	if !exists {
+		return value, MapKeyNotFound
+	}
+	return n.value, nil
+}
+
+// ToMap converts the map instance to a native map
+func (m *lruMap[K, V]) ToMap() map[K]V {
+	m.Lock()
+	defer m.Unlock()
+
+	result := make(map[K]V, len(m.items))
+	for k, n := range m.items {
+		result[k] = n.value
+	}
+	return result
+}
+
+// Range iterates from most- to least-recently-used, invoking fn for each
+// entry without affecting recency. Iteration stops early if fn returns false.
+func (m *lruMap[K, V]) Range(fn func(key K, val V) bool) {
+	m.Lock()
+	defer m.Unlock()
+
+	for n := m.head; n != nil; n = n.next {
+		if !fn(n.key, n.value) {
+			return
+		}
+	}
+}
+
+// Keys returns all keys currently in the map, most- to least-recently-used.
+func (m *lruMap[K, V]) Keys() []K {
+	keys := make([]K, 0, m.Len())
+	m.Range(func(key K, _ V) bool {
+		keys = append(keys, key)
+		return true
+	})
+	return keys
+}
+
+// Values returns all values currently in the map, most- to least-recently-used.
+func (m *lruMap[K, V]) Values() []V {
+	values := make([]V, 0, m.Len())
+	m.Range(func(_ K, val V) bool {
+		values = append(values, val)
+		return true
+	})
+	return values
+}
+
+// Len returns the number of entries currently in the map
+func (m *lruMap[K, V]) Len() int {
+	m.Lock()
+	defer m.Unlock()
+
+	return len(m.items)
+}
+
+// Compute atomically computes a new value for key, marking it
+// most-recently-used unless fn requests a delete.
+func (m *lruMap[K, V]) Compute(key K, fn func(oldVal V, loaded bool) (newVal V, del bool)) (actual V, ok bool) {
+	m.Lock()
+	defer m.Unlock()
+
+	n, loaded := m.items[key]
+	var oldVal V
+	if loaded {
+		oldVal = n.value
+	}
+
+	newVal, del := fn(oldVal, loaded)
+	if del {
+		if loaded {
+			m.unlinkLocked(n)
+			delete(m.items, key)
+		}
+		return newVal, false
+	}
+
+	if loaded {
+		n.value = newVal
+		m.touchLocked(n)
+	} else {
+		m.insertLocked(key, newVal)
+	}
+	return newVal, true
+}
+
+// LoadOrCompute returns the existing value for key if present, otherwise it
+// stores and returns the result of fn. Either way the key becomes
+// most-recently-used.
+func (m *lruMap[K, V]) LoadOrCompute(key K, fn func() V) (value V, loaded bool) {
+	m.Lock()
+	defer m.Unlock()
+
+	if n, exists := m.items[key]; exists {
+		m.touchLocked(n)
+		return n.value, true
+	}
+
+	value = fn()
+	m.insertLocked(key, value)
+	return value, false
+}
+
+// Swap stores val as the new value for key and returns the value it
+// replaced, if any. Either way the key becomes most-recently-used.
+func (m *lruMap[K, V]) Swap(key K, val V) (previous V, loaded bool) {
+	m.Lock()
+	defer m.Unlock()
+
+	if n, exists := m.items[key]; exists {
+		previous, loaded = n.value, true
+		n.value = val
+		m.touchLocked(n)
+		return
+	}
+
+	m.insertLocked(key, val)
+	return
+}
@@ -0,0 +1,61 @@
+package structures
+
+import "testing"
+
+func TestLRUMapEvictsLeastRecentlyUsed(t *testing.T) {
+	var evicted []string
+	m := NewLRUMap[string, int](2, func(key string, val int) {
+		evicted = append(evicted, key)
+	})
+
+	_ = m.Add("a", 1)
+	_ = m.Add("b", 2)
+	_ = m.Add("c", 3) // evicts a, since b and c are now more recent
+
+	if len(evicted) != 1 || evicted[0] != "a" {
+		t.Fatalf("evicted = %v, want [a]", evicted)
+	}
+	if m.Contains("a") {
+		t.Fatalf("a should have been evicted")
+	}
+	if !m.Contains("b") || !m.Contains("c") {
+		t.Fatalf("b and c should still be present")
+	}
+}
+
+func TestLRUMapGetRefreshesRecency(t *testing.T) {
+	m := NewLRUMap[string, int](2)
+
+	_ = m.Add("a", 1)
+	_ = m.Add("b", 2)
+
+	if _, err := m.Get("a"); err != nil {
+		t.Fatalf("Get(a) error = %v", err)
+	}
+
+	_ = m.Add("c", 3) // b is now least-recently-used, not a
+
+	if m.Contains("b") {
+		t.Fatalf("b should have been evicted after a was refreshed by Get")
+	}
+	if !m.Contains("a") || !m.Contains("c") {
+		t.Fatalf("a and c should still be present")
+	}
+}
+
+func TestLRUMapPeekDoesNotRefreshRecency(t *testing.T) {
+	m := NewLRUMap[string, int](2)
+
+	_ = m.Add("a", 1)
+	_ = m.Add("b", 2)
+
+	if _, err := m.Peek("a"); err != nil {
+		t.Fatalf("Peek(a) error = %v", err)
+	}
+
+	_ = m.Add("c", 3) // a is still least-recently-used since Peek must not count
+
+	if m.Contains("a") {
+		t.Fatalf("a should have been evicted; Peek must not refresh recency")
+	}
+}
@@ -0,0 +1,141 @@
+package structures
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// mapJSONEntry is the array-form JSON representation of a single Map entry,
+// used when K is not a string or integer type.
+type mapJSONEntry[K comparable, V any] struct {
+	K K `json:"k"`
+	V V `json:"v"`
+}
+
+// MarshalJSON flattens the shadow chain via ToMap and encodes the result.
+// If K is a string or integer type it is emitted as a plain JSON object
+// keyed by the stringified key; otherwise (including floating-point keys)
+// it is emitted as an array of {"k":..,"v":..} entries.
+func (t *builtinMap[K, V]) MarshalJSON() ([]byte, error) {
+	m := t.ToMap()
+
+	if useObjectForm[K]() {
+		obj := make(map[string]V, len(m))
+		for k, v := range m {
+			obj[fmt.Sprint(k)] = v
+		}
+		return json.Marshal(obj)
+	}
+
+	entries := make([]mapJSONEntry[K, V], 0, len(m))
+	for k, v := range m {
+		entries = append(entries, mapJSONEntry[K, V]{K: k, V: v})
+	}
+	return json.Marshal(entries)
+}
+
+// UnmarshalJSON accepts either JSON form produced by MarshalJSON and
+// replaces the map's local contents, dropping any shadow and tombstones.
+func (t *builtinMap[K, V]) UnmarshalJSON(data []byte) error {
+	trimmed := bytes.TrimLeft(data, " \t\r\n")
+	if len(trimmed) == 0 {
+		return nil
+	}
+
+	table := make(map[K]V)
+
+	switch trimmed[0] {
+	case '{':
+		var obj map[string]V
+		if err := json.Unmarshal(data, &obj); err != nil {
+			return err
+		}
+		for keyStr, v := range obj {
+			key, err := parseJSONKey[K](keyStr)
+			if err != nil {
+				return err
+			}
+			table[key] = v
+		}
+	case '[':
+		var entries []mapJSONEntry[K, V]
+		if err := json.Unmarshal(data, &entries); err != nil {
+			return err
+		}
+		for _, e := range entries {
+			table[e.K] = e.V
+		}
+	default:
+		return errors.New("structures: invalid JSON payload for map")
+	}
+
+	t.Lock()
+	defer t.Unlock()
+
+	t.table = table
+	t.tombstones = make(map[K]struct{})
+	t.shadow = nil
+	return nil
+}
+
+// GobEncode flattens the shadow chain via ToMap and gob-encodes the result.
+func (t *builtinMap[K, V]) GobEncode() ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(t.ToMap()); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// GobDecode replaces the map's local contents with the decoded data,
+// dropping any shadow and tombstones.
+func (t *builtinMap[K, V]) GobDecode(data []byte) error {
+	var table map[K]V
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&table); err != nil {
+		return err
+	}
+
+	t.Lock()
+	defer t.Unlock()
+
+	t.table = table
+	t.tombstones = make(map[K]struct{})
+	t.shadow = nil
+	return nil
+}
+
+// DumpJSON writes m's JSON encoding to w. m must implement json.Marshaler
+// (as maps constructed with NewMap do); otherwise DumpJSON errors rather
+// than silently encoding m's unexported fields as "{}".
+func DumpJSON[K comparable, V any](w io.Writer, m Map[K, V]) error {
+	if _, ok := m.(json.Marshaler); !ok {
+		return fmt.Errorf("structures: %T does not support JSON serialization", m)
+	}
+
+	data, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+// LoadJSON reads a JSON encoding produced by DumpJSON from r and replaces
+// m's contents. m must implement json.Unmarshaler (as maps constructed with
+// NewMap do); otherwise LoadJSON errors rather than silently leaving m
+// unchanged.
+func LoadJSON[K comparable, V any](r io.Reader, m Map[K, V]) error {
+	if _, ok := m.(json.Unmarshaler); !ok {
+		return fmt.Errorf("structures: %T does not support JSON deserialization", m)
+	}
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, m)
+}
@@ -0,0 +1,41 @@
+package structures
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestDumpJSONRejectsUnsupportedMap(t *testing.T) {
+	m := NewShardedMap[string, int](4)
+	_ = m.Set("a", 1)
+
+	var buf bytes.Buffer
+	if err := DumpJSON[string, int](&buf, m); err == nil {
+		t.Fatalf("DumpJSON on a shardedMap should error instead of silently writing %q", buf.String())
+	}
+}
+
+func TestLoadJSONRejectsUnsupportedMap(t *testing.T) {
+	m := NewShardedMap[string, int](4)
+	if err := LoadJSON[string, int](bytes.NewReader([]byte(`{"a":1}`)), m); err == nil {
+		t.Fatalf("LoadJSON on a shardedMap should error instead of silently doing nothing")
+	}
+}
+
+func TestDumpJSONRoundTripsBuiltinMap(t *testing.T) {
+	m := NewMap[string, int]()
+	_ = m.Set("a", 1)
+
+	var buf bytes.Buffer
+	if err := DumpJSON[string, int](&buf, m); err != nil {
+		t.Fatalf("DumpJSON() error = %v", err)
+	}
+
+	loaded := NewMap[string, int]()
+	if err := LoadJSON[string, int](&buf, loaded); err != nil {
+		t.Fatalf("LoadJSON() error = %v", err)
+	}
+	if v, err := loaded.Get("a"); err != nil || v != 1 {
+		t.Fatalf("loaded.Get(a) = %d, %v, want 1, nil", v, err)
+	}
+}
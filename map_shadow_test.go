@@ -0,0 +1,84 @@
+package structures
+
+import "testing"
+
+func TestMapShadowCopyCommit(t *testing.T) {
+	base := NewMap[string, int]()
+	_ = base.Set("a", 1)
+	_ = base.Set("b", 2)
+
+	overlay := base.ShadowCopy()
+	_ = overlay.Set("a", 10)
+	_ = overlay.Delete("b")
+	_ = overlay.Set("c", 3)
+
+	if v, err := overlay.Get("a"); err != nil || v != 10 {
+		t.Fatalf("Get(a) through overlay = %d, %v, want 10, nil", v, err)
+	}
+	if overlay.Contains("b") {
+		t.Fatalf("overlay should not see b after local delete")
+	}
+	if v, _ := base.Get("a"); v != 1 {
+		t.Fatalf("base should be unaffected before Commit, got a=%d", v)
+	}
+
+	if err := overlay.Commit(); err != nil {
+		t.Fatalf("Commit() error = %v", err)
+	}
+
+	if v, _ := base.Get("a"); v != 10 {
+		t.Fatalf("base.Get(a) after Commit = %d, want 10", v)
+	}
+	if base.Contains("b") {
+		t.Fatalf("base should have dropped b after Commit")
+	}
+	if v, _ := base.Get("c"); v != 3 {
+		t.Fatalf("base.Get(c) after Commit = %d, want 3", v)
+	}
+	if overlay.Len() != base.Len() {
+		t.Fatalf("overlay.Len() = %d, base.Len() = %d, want equal after Commit cleared the local layer", overlay.Len(), base.Len())
+	}
+}
+
+func TestMapShadowCopyDiscard(t *testing.T) {
+	base := NewMap[string, int]()
+	_ = base.Set("a", 1)
+
+	overlay := base.ShadowCopy()
+	_ = overlay.Set("a", 99)
+	_ = overlay.Set("b", 2)
+
+	overlay.Discard()
+
+	if v, _ := overlay.Get("a"); v != 1 {
+		t.Fatalf("overlay.Get(a) after Discard = %d, want 1 (reads through to shadow)", v)
+	}
+	if overlay.Contains("b") {
+		t.Fatalf("overlay should not see b after Discard")
+	}
+}
+
+func TestMapShadowCopyFlatten(t *testing.T) {
+	base := NewMap[string, int]()
+	_ = base.Set("a", 1)
+
+	overlay := base.ShadowCopy()
+	_ = overlay.Set("b", 2)
+
+	flat := overlay.Flatten()
+	if flat.Len() != 2 {
+		t.Fatalf("flat.Len() = %d, want 2", flat.Len())
+	}
+
+	_ = base.Set("a", 100)
+	if v, _ := flat.Get("a"); v != 1 {
+		t.Fatalf("flat.Get(a) = %d, want 1 (flatten must not track the shadow anymore)", v)
+	}
+}
+
+func TestMapCommitWithoutShadowErrors(t *testing.T) {
+	m := NewMap[string, int]()
+	if err := m.Commit(); err == nil {
+		t.Fatalf("Commit() on a map with no shadow should error")
+	}
+}
@@ -0,0 +1,169 @@
+package structures
+
+import "runtime"
+
+// DefaultShardCount returns the shard count used by NewShardedMap and
+// NewShardedTable when none is given: GOMAXPROCS*16, rounded up to the next
+// power of two so shard selection can use a bitmask.
+func DefaultShardCount() int {
+	return nextPowerOfTwo(runtime.GOMAXPROCS(0) * 16)
+}
+
+// shardedMap stripes its contents across a fixed number of builtinMap
+// shards, each guarded by its own RWMutex, so that a write to one shard
+// never blocks reads or writes against another.
+//
+// shardedMap does not support the ShadowedMap overlay operations (ShadowCopy,
+// Commit, Discard, Flatten): a shadow copy of a sharded map would need one
+// shadow per shard, sharing the parent's shard count and hash function, and
+// Commit would need to merge each shard independently under its own lock.
+// NewShardedMap does not build that plumbing, so it returns a plain Map[K,V]
+// rather than a ShadowedMap[K,V].
+type shardedMap[K comparable, V any] struct {
+	shards []*builtinMap[K, V]
+	mask   uint64
+	hasher Hasher[K]
+}
+
+// NewShardedMap creates a Map that stripes its contents across shards
+// shards to remove the single-RWMutex bottleneck of NewMap under mixed
+// read/write workloads. shards is rounded up to the next power of two; if
+// shards <= 0, DefaultShardCount is used. An optional Hasher may be supplied
+// to control shard assignment for key types defaultHasher does not know
+// about.
+func NewShardedMap[K comparable, V any](shards int, hasher ...Hasher[K]) Map[K, V] {
+	if shards <= 0 {
+		shards = DefaultShardCount()
+	}
+	shards = nextPowerOfTwo(shards)
+
+	h := defaultHasher[K]()
+	if len(hasher) > 0 && hasher[0] != nil {
+		h = hasher[0]
+	}
+
+	m := &shardedMap[K, V]{
+		shards: make([]*builtinMap[K, V], shards),
+		mask:   uint64(shards - 1),
+		hasher: h,
+	}
+	for i := range m.shards {
+		m.shards[i] = NewMap[K, V]().(*builtinMap[K, V])
+	}
+	return m
+}
+
+// shardFor returns the shard that owns key
+func (m *shardedMap[K, V]) shardFor(key K) *builtinMap[K, V] {
+	return m.shards[m.hasher(key)&m.mask]
+}
+
+// Add stores a new value by the given key or will error if the key already exists
+func (m *shardedMap[K, V]) Add(key K, val V) error {
+	return m.shardFor(key).Add(key, val)
+}
+
+// Contains returns a whether the given key is contained in the hashmap or not.
+func (m *shardedMap[K, V]) Contains(key K) bool {
+	return m.shardFor(key).Contains(key)
+}
+
+// Delete deletes a value by key, if key does not exist an error will be returned.
+func (m *shardedMap[K, V]) Delete(key K) error {
+	return m.shardFor(key).Delete(key)
+}
+
+// Get returns the value by key, if key does not exist an error will be returned.
+func (m *shardedMap[K, V]) Get(key K) (V, error) {
+	return m.shardFor(key).Get(key)
+}
+
+// GetOrDefault returns the value by key, if key does not exist def will be returned.
+func (m *shardedMap[K, V]) GetOrDefault(key K, def V) V {
+	return m.shardFor(key).GetOrDefault(key, def)
+}
+
+// GetOrSet returns the value by key, if key does not exist def will be returned and stored.
+func (m *shardedMap[K, V]) GetOrSet(key K, def V) V {
+	return m.shardFor(key).GetOrSet(key, def)
+}
+
+// Set stores val as a new value by key
+func (m *shardedMap[K, V]) Set(key K, val V) error {
+	return m.shardFor(key).Set(key, val)
+}
+
+// ToMap converts the map instance to a native map
+func (m *shardedMap[K, V]) ToMap() map[K]V {
+	result := make(map[K]V, m.Len())
+	for _, shard := range m.shards {
+		for k, v := range shard.ToMap() {
+			result[k] = v
+		}
+	}
+	return result
+}
+
+// Range iterates over every key/value pair across all shards, invoking fn
+// for each of them. Iteration stops early if fn returns false.
+func (m *shardedMap[K, V]) Range(fn func(key K, val V) bool) {
+	for _, shard := range m.shards {
+		stopped := false
+		shard.Range(func(key K, val V) bool {
+			if !fn(key, val) {
+				stopped = true
+				return false
+			}
+			return true
+		})
+		if stopped {
+			return
+		}
+	}
+}
+
+// Keys returns all keys currently visible in the map
+func (m *shardedMap[K, V]) Keys() []K {
+	keys := make([]K, 0, m.Len())
+	m.Range(func(key K, _ V) bool {
+		keys = append(keys, key)
+		return true
+	})
+	return keys
+}
+
+// Values returns all values currently visible in the map
+func (m *shardedMap[K, V]) Values() []V {
+	values := make([]V, 0, m.Len())
+	m.Range(func(_ K, val V) bool {
+		values = append(values, val)
+		return true
+	})
+	return values
+}
+
+// Compute atomically computes a new value for key under its shard's write lock.
+func (m *shardedMap[K, V]) Compute(key K, fn func(oldVal V, loaded bool) (newVal V, del bool)) (actual V, ok bool) {
+	return m.shardFor(key).Compute(key, fn)
+}
+
+// LoadOrCompute returns the existing value for key if one is visible,
+// otherwise it stores and returns the result of fn.
+func (m *shardedMap[K, V]) LoadOrCompute(key K, fn func() V) (value V, loaded bool) {
+	return m.shardFor(key).LoadOrCompute(key, fn)
+}
+
+// Swap stores val as the new value for key and returns the value it
+// replaced, if any.
+func (m *shardedMap[K, V]) Swap(key K, val V) (previous V, loaded bool) {
+	return m.shardFor(key).Swap(key, val)
+}
+
+// Len returns the number of entries currently visible in the map
+func (m *shardedMap[K, V]) Len() int {
+	count := 0
+	for _, shard := range m.shards {
+		count += shard.Len()
+	}
+	return count
+}
@@ -0,0 +1,36 @@
+package structures
+
+import (
+	"strconv"
+	"testing"
+)
+
+// benchmarkMapMixed hammers m with a mix of reads and writes from multiple
+// goroutines, the workload NewShardedMap is meant to help with over NewMap.
+func benchmarkMapMixed(b *testing.B, m Map[string, int]) {
+	for i := 0; i < 1024; i++ {
+		_ = m.Set(strconv.Itoa(i), i)
+	}
+
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			key := strconv.Itoa(i % 1024)
+			if i%10 == 0 {
+				_ = m.Set(key, i)
+			} else {
+				m.Get(key)
+			}
+			i++
+		}
+	})
+}
+
+func BenchmarkMap_SingleMutex(b *testing.B) {
+	benchmarkMapMixed(b, NewMap[string, int]())
+}
+
+func BenchmarkMap_Sharded(b *testing.B) {
+	benchmarkMapMixed(b, NewShardedMap[string, int](DefaultShardCount()))
+}
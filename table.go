@@ -25,4 +25,55 @@ type Table[K1 comparable, K2 comparable, V any] interface {
 	Set(k1 K1, k2 K2, newVal V) error
 	// ToMap converts the table instance to a native map
 	ToMap() map[K1]map[K2]V
+	// Range iterates over every key/value pair, invoking fn for each of them.
+	// Iteration stops early if fn returns false.
+	Range(fn func(k1 K1, k2 K2, val V) bool)
+	// Keys returns all key pairs currently visible in the table
+	Keys() []TableKey[K1, K2]
+	// Values returns all values currently visible in the table
+	Values() []V
+	// Len returns the number of entries currently visible in the table
+	Len() int
+	// Compute atomically computes a new value for the given keys under the
+	// write lock. fn receives the current value and whether it was found (in
+	// the local table or, failing that, the shadow) and returns the value to
+	// store and whether the keys should be deleted instead. actual is the
+	// value fn returned; ok is false if the keys were deleted.
+	Compute(k1 K1, k2 K2, fn func(oldVal V, loaded bool) (newVal V, del bool)) (actual V, ok bool)
+	// LoadOrCompute returns the existing value for the given keys if one is
+	// visible, otherwise it stores and returns the result of fn. loaded is
+	// true if an existing value was returned instead of fn's result.
+	LoadOrCompute(k1 K1, k2 K2, fn func() V) (value V, loaded bool)
+	// Swap stores val as the new value for the given keys and returns the
+	// value it replaced, if any.
+	Swap(k1 K1, k2 K2, val V) (previous V, loaded bool)
+}
+
+// TableKey addresses a single value within a Table by its two keys
+type TableKey[K1 comparable, K2 comparable] struct {
+	K1 K1
+	K2 K2
+}
+
+// ShadowedTable extends Table with the copy-on-write overlay operations
+// supported by tables constructed with NewTable: ShadowCopy, Commit, Discard
+// and Flatten.
+type ShadowedTable[K1 comparable, K2 comparable, V any] interface {
+	Table[K1, K2, V]
+	// ShadowCopy returns a new table that reads through to this table for
+	// any keys not overridden locally.
+	ShadowCopy() ShadowedTable[K1, K2, V]
+	// Commit merges this table's local writes and deletes into its shadow,
+	// then clears the local layer. Each write into the shadow takes the
+	// shadow's lock on its own, so a reader of the shadow can see a partly
+	// applied commit; if a write into the shadow fails, Commit returns that
+	// error with the earlier writes already applied and the local layer
+	// kept. It errors if this table has no shadow to commit into.
+	Commit() error
+	// Discard empties the local layer, undoing every write and delete made
+	// since the last Commit, without touching the shadow.
+	Discard()
+	// Flatten returns a new, shadow-free table materializing the full view
+	// currently visible through this table.
+	Flatten() Table[K1, K2, V]
 }
@@ -7,21 +7,58 @@ import (
 
 // Table is a wrapper for a 3 dimensional map that manages its contents
 type builtinTable[K1 comparable, K2 comparable, V2 any] struct {
-	table  map[K1]map[K2]V2
-	shadow Table[K1, K2, V2]
+	table      map[K1]map[K2]V2
+	tombstones map[K1]map[K2]struct{}
+	shadow     Table[K1, K2, V2]
 	sync.RWMutex
 }
 
+// isTombstoned reports whether k1/k2 was deleted locally while still
+// present in the shadow
+func (t *builtinTable[K1, K2, V2]) isTombstoned(k1 K1, k2 K2) bool {
+	if table2, exists := t.tombstones[k1]; exists {
+		_, tombstoned := table2[k2]
+		return tombstoned
+	}
+	return false
+}
+
+// clearTombstone removes any tombstone recorded for k1/k2
+func (t *builtinTable[K1, K2, V2]) clearTombstone(k1 K1, k2 K2) {
+	if table2, exists := t.tombstones[k1]; exists {
+		delete(table2, k2)
+	}
+}
+
+// tombstone records k1/k2 as deleted locally
+func (t *builtinTable[K1, K2, V2]) tombstone(k1 K1, k2 K2) {
+	if _, exists := t.tombstones[k1]; !exists {
+		t.tombstones[k1] = make(map[K2]struct{})
+	}
+	t.tombstones[k1][k2] = struct{}{}
+}
+
 // Add stores a new value by the given keys or will error if the keys already exist
 func (t *builtinTable[K1, K2, V2]) Add(k1 K1, k2 K2, newVal V2) error {
 	t.Lock()
 	defer t.Unlock()
 
+	return t.addLocked(k1, k2, newVal)
+}
+
+// addLocked is the body of Add; the caller must already hold the write lock.
+func (t *builtinTable[K1, K2, V2]) addLocked(k1 K1, k2 K2, newVal V2) error {
 	if _, exists := t.table[k1]; !exists {
 		t.table[k1] = make(map[K2]V2)
 	} else if _, exists := t.table[k1][k2]; exists {
 		return TableDuplicateKeys
 	}
+
+	if !t.isTombstoned(k1, k2) && t.shadow != nil && t.shadow.Contains(k1, k2) {
+		return TableDuplicateKeys
+	}
+
+	t.clearTombstone(k1, k2)
 	t.table[k1][k2] = newVal
 
 	return nil
@@ -32,6 +69,10 @@ func (t *builtinTable[K1, K2, V2]) Contains(k1 K1, k2 K2) bool {
 	t.RLock()
 	defer t.RUnlock()
 
+	if t.isTombstoned(k1, k2) {
+		return false
+	}
+
 	if table2, exists := t.table[k1]; exists {
 		if _, exists := table2[k2]; exists {
 			return true
@@ -44,20 +85,32 @@ func (t *builtinTable[K1, K2, V2]) Contains(k1 K1, k2 K2) bool {
 	return false
 }
 
-// Delete deletes the value by its keys, if the keys does not exist an error will be returned.
+// Delete deletes the value by its keys, if the keys does not exist an error
+// will be returned. Deleting keys that only exist in the shadow records a
+// tombstone locally instead of touching the shadow, so the keys disappear
+// from this table's view until Commit or Discard is called.
 func (t *builtinTable[K1, K2, V2]) Delete(k1 K1, k2 K2) error {
 	t.Lock()
 	defer t.Unlock()
 
+	return t.deleteLocked(k1, k2)
+}
+
+// deleteLocked is the body of Delete; the caller must already hold the write lock.
+func (t *builtinTable[K1, K2, V2]) deleteLocked(k1 K1, k2 K2) error {
 	if table2, exists := t.table[k1]; exists {
 		if _, exists := table2[k2]; exists {
 			delete(t.table[k1], k2)
+			if t.shadow != nil {
+				t.tombstone(k1, k2)
+			}
 			return nil
 		}
 	}
 
-	if t.shadow != nil && t.shadow.Contains(k1, k2) {
-		return errors.New("cannot delete from shadow table")
+	if !t.isTombstoned(k1, k2) && t.shadow != nil && t.shadow.Contains(k1, k2) {
+		t.tombstone(k1, k2)
+		return nil
 	}
 
 	return TableKeysNotFound
@@ -68,6 +121,10 @@ func (t *builtinTable[K1, K2, V2]) Get(k1 K1, k2 K2) (value V2, err error) {
 	t.RLock()
 	defer t.RUnlock()
 
+	if t.isTombstoned(k1, k2) {
+		return value, TableKeysNotFound
+	}
+
 	err = TableKeysNotFound
 	if table2, exists := t.table[k1]; exists {
 		if val, exists := table2[k2]; exists {
@@ -88,6 +145,10 @@ func (t *builtinTable[K1, K2, V2]) GetOrDefault(k1 K1, k2 K2, def V2) (value V2)
 	t.RLock()
 	defer t.RUnlock()
 
+	if t.isTombstoned(k1, k2) {
+		return def
+	}
+
 	if table2, exists := t.table[k1]; exists {
 		if val, exists := table2[k2]; exists {
 			return val
@@ -106,28 +167,29 @@ func (t *builtinTable[K1, K2, V2]) GetOrSet(k1 K1, k2 K2, newVal V2) (value V2)
 	t.Lock()
 	defer t.Unlock()
 
+	return t.getOrSetLocked(k1, k2, newVal)
+}
+
+// getOrSetLocked is the body of GetOrSet; the caller must already hold the write lock.
+func (t *builtinTable[K1, K2, V2]) getOrSetLocked(k1 K1, k2 K2, newVal V2) (value V2) {
 	if table2, exists := t.table[k1]; exists {
 		if val, exists := table2[k2]; exists {
 			return val
-		} else if t.shadow != nil && t.shadow.Contains(k1, k2) {
-			if v, err := t.shadow.Get(k1, k2); err == nil {
-				return v
-			}
-		} else {
-			table2[k2] = newVal
-			return newVal
 		}
-	} else if t.shadow != nil && t.shadow.Contains(k1, k2) {
+	}
+
+	if !t.isTombstoned(k1, k2) && t.shadow != nil && t.shadow.Contains(k1, k2) {
 		if v, err := t.shadow.Get(k1, k2); err == nil {
 			return v
 		}
-	} else {
-		t.table[k1] = make(map[K2]V2)
-		t.table[k1][k2] = newVal
-		return newVal
 	}
 
-	return
+	if _, exists := t.table[k1]; !exists {
+		t.table[k1] = make(map[K2]V2)
+	}
+	t.clearTombstone(k1, k2)
+	t.table[k1][k2] = newVal
+	return newVal
 }
 
 // Set stores a new value by the given keys
@@ -135,21 +197,172 @@ func (t *builtinTable[K1, K2, V2]) Set(k1 K1, k2 K2, newVal V2) error {
 	t.Lock()
 	defer t.Unlock()
 
+	return t.setLocked(k1, k2, newVal)
+}
+
+// setLocked is the body of Set; the caller must already hold the write lock.
+func (t *builtinTable[K1, K2, V2]) setLocked(k1 K1, k2 K2, newVal V2) error {
 	if _, exists := t.table[k1]; !exists {
 		t.table[k1] = make(map[K2]V2)
 	}
+	t.clearTombstone(k1, k2)
 	t.table[k1][k2] = newVal
 
 	return nil
 }
 
+// Range iterates over every key/value pair, invoking fn for each of them.
+// Iteration stops early if fn returns false. Keys already visited in the
+// local table are not visited again when iterating the shadow.
+func (t *builtinTable[K1, K2, V2]) Range(fn func(k1 K1, k2 K2, val V2) bool) {
+	t.RLock()
+	defer t.RUnlock()
+
+	t.rangeLocked(fn)
+}
+
+// rangeLocked is the body of Range; the caller must already hold at least a read lock.
+func (t *builtinTable[K1, K2, V2]) rangeLocked(fn func(k1 K1, k2 K2, val V2) bool) {
+	for k1, table2 := range t.table {
+		for k2, v := range table2 {
+			if !fn(k1, k2, v) {
+				return
+			}
+		}
+	}
+
+	if t.shadow != nil {
+		t.shadow.Range(func(k1 K1, k2 K2, v V2) bool {
+			if table2, exists := t.table[k1]; exists {
+				if _, exists := table2[k2]; exists {
+					return true
+				}
+			}
+			if t.isTombstoned(k1, k2) {
+				return true
+			}
+			return fn(k1, k2, v)
+		})
+	}
+}
+
+// Keys returns all key pairs currently visible in the table
+func (t *builtinTable[K1, K2, V2]) Keys() []TableKey[K1, K2] {
+	keys := make([]TableKey[K1, K2], 0, t.Len())
+	t.Range(func(k1 K1, k2 K2, _ V2) bool {
+		keys = append(keys, TableKey[K1, K2]{K1: k1, K2: k2})
+		return true
+	})
+	return keys
+}
+
+// Values returns all values currently visible in the table
+func (t *builtinTable[K1, K2, V2]) Values() []V2 {
+	values := make([]V2, 0, t.Len())
+	t.Range(func(_ K1, _ K2, val V2) bool {
+		values = append(values, val)
+		return true
+	})
+	return values
+}
+
+// Len returns the number of entries currently visible in the table
+func (t *builtinTable[K1, K2, V2]) Len() int {
+	t.RLock()
+	defer t.RUnlock()
+
+	count := 0
+	for _, table2 := range t.table {
+		count += len(table2)
+	}
+
+	if t.shadow != nil {
+		t.shadow.Range(func(k1 K1, k2 K2, _ V2) bool {
+			if table2, exists := t.table[k1]; exists {
+				if _, exists := table2[k2]; exists {
+					return true
+				}
+			}
+			if t.isTombstoned(k1, k2) {
+				return true
+			}
+			count++
+			return true
+		})
+	}
+	return count
+}
+
 // ShadowCopy returns a new table with the current table as its shadow
-func (t *builtinTable[K1, K2, V]) ShadowCopy() Table[K1, K2, V] {
+func (t *builtinTable[K1, K2, V]) ShadowCopy() ShadowedTable[K1, K2, V] {
 	newTable := NewTable[K1, K2, V]()
 	newTable.(*builtinTable[K1, K2, V]).shadow = t
 	return newTable
 }
 
+// Commit merges this table's local writes and deletes into its shadow:
+// stored values are written through via Set and tombstoned keys are deleted,
+// then the local layer is cleared. This table's write lock is held
+// throughout, but each Set or Delete on the shadow locks the shadow
+// separately, so readers of the shadow can observe a partly applied commit.
+// If the shadow rejects a write, Commit returns that error with the earlier
+// writes already applied and the local layer kept. It errors if this table
+// has no shadow to commit into.
+func (t *builtinTable[K1, K2, V]) Commit() error {
+	t.Lock()
+	defer t.Unlock()
+
+	if t.shadow == nil {
+		return errors.New("table has no shadow to commit into")
+	}
+
+	for k1, table2 := range t.tombstones {
+		for k2 := range table2 {
+			if err := t.shadow.Delete(k1, k2); err != nil && err != TableKeysNotFound {
+				return err
+			}
+		}
+	}
+
+	for k1, table2 := range t.table {
+		for k2, v := range table2 {
+			if err := t.shadow.Set(k1, k2, v); err != nil {
+				return err
+			}
+		}
+	}
+
+	t.table = make(map[K1]map[K2]V)
+	t.tombstones = make(map[K1]map[K2]struct{})
+	return nil
+}
+
+// Discard empties the local layer, undoing every write and delete made
+// since the last Commit, without touching the shadow.
+func (t *builtinTable[K1, K2, V]) Discard() {
+	t.Lock()
+	defer t.Unlock()
+
+	t.discardLocked()
+}
+
+// discardLocked is the body of Discard; the caller must already hold the write lock.
+func (t *builtinTable[K1, K2, V]) discardLocked() {
+	t.table = make(map[K1]map[K2]V)
+	t.tombstones = make(map[K1]map[K2]struct{})
+}
+
+// Flatten returns a new, shadow-free table materializing the full view
+// currently visible through this table.
+func (t *builtinTable[K1, K2, V]) Flatten() Table[K1, K2, V] {
+	flat := NewTable[K1, K2, V]()
+	t.Range(func(k1 K1, k2 K2, val V) bool {
+		_ = flat.Set(k1, k2, val)
+		return true
+	})
+	return flat
+}
+
 // ToMap converts the map instance to a native map
 func (t *builtinTable[K1, K2, V]) ToMap() map[K1]map[K2]V {
 	t.RLock()
@@ -162,6 +375,9 @@ func (t *builtinTable[K1, K2, V]) ToMap() map[K1]map[K2]V {
 	m := NewTable[K1, K2, V]()
 	for k1, table2 := range t.shadow.ToMap() {
 		for k2, v := range table2 {
+			if t.isTombstoned(k1, k2) {
+				continue
+			}
 			m.Set(k1, k2, v)
 		}
 	}
@@ -174,10 +390,12 @@ func (t *builtinTable[K1, K2, V]) ToMap() map[K1]map[K2]V {
 	return m.ToMap()
 }
 
-// NewTable will create a new, empty instance of Table
-func NewTable[K1 comparable, K2 comparable, V any]() Table[K1, K2, V] {
+// NewTable will create a new, empty instance of Table, with support for
+// copy-on-write shadow overlays via ShadowedTable
+func NewTable[K1 comparable, K2 comparable, V any]() ShadowedTable[K1, K2, V] {
 	return &builtinTable[K1, K2, V]{
-		table:   make(map[K1]map[K2]V),
-		RWMutex: sync.RWMutex{},
+		table:      make(map[K1]map[K2]V),
+		tombstones: make(map[K1]map[K2]struct{}),
+		RWMutex:    sync.RWMutex{},
 	}
 }
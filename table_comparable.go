@@ -0,0 +1,88 @@
+package structures
+
+// ComparableTable extends Table with atomic compare-and-swap/compare-and-
+// delete operations. These require value equality, so they are only
+// available for tables constructed with NewComparableTable.
+type ComparableTable[K1 comparable, K2 comparable, V comparable] interface {
+	Table[K1, K2, V]
+	// CompareAndSwap stores newVal for the given keys if and only if the
+	// value currently visible for them equals old, returning whether the
+	// swap happened.
+	CompareAndSwap(k1 K1, k2 K2, old, newVal V) bool
+	// CompareAndDelete deletes the given keys if and only if the value
+	// currently visible for them equals old, returning whether the delete
+	// happened.
+	CompareAndDelete(k1 K1, k2 K2, old V) bool
+}
+
+// comparableTable adds CompareAndSwap/CompareAndDelete on top of builtinTable
+type comparableTable[K1 comparable, K2 comparable, V comparable] struct {
+	*builtinTable[K1, K2, V]
+}
+
+// NewComparableTable will create a new, empty instance of ComparableTable
+func NewComparableTable[K1 comparable, K2 comparable, V comparable]() ComparableTable[K1, K2, V] {
+	return &comparableTable[K1, K2, V]{builtinTable: NewTable[K1, K2, V]().(*builtinTable[K1, K2, V])}
+}
+
+// CompareAndSwap stores newVal for the given keys if and only if the value
+// currently visible for them equals old, returning whether the swap happened.
+func (t *comparableTable[K1, K2, V]) CompareAndSwap(k1 K1, k2 K2, old, newVal V) bool {
+	t.Lock()
+	defer t.Unlock()
+
+	var current V
+	exists := false
+	if table2, ok := t.table[k1]; ok {
+		if v, ok := table2[k2]; ok {
+			current, exists = v, true
+		}
+	}
+	if !exists && !t.isTombstoned(k1, k2) && t.shadow != nil {
+		if v, err := t.shadow.Get(k1, k2); err == nil {
+			current, exists = v, true
+		}
+	}
+
+	if !exists || current != old {
+		return false
+	}
+
+	if _, ok := t.table[k1]; !ok {
+		t.table[k1] = make(map[K2]V)
+	}
+	t.clearTombstone(k1, k2)
+	t.table[k1][k2] = newVal
+	return true
+}
+
+// CompareAndDelete deletes the given keys if and only if the value
+// currently visible for them equals old, returning whether the delete
+// happened.
+func (t *comparableTable[K1, K2, V]) CompareAndDelete(k1 K1, k2 K2, old V) bool {
+	t.Lock()
+	defer t.Unlock()
+
+	var current V
+	exists := false
+	if table2, ok := t.table[k1]; ok {
+		if v, ok := table2[k2]; ok {
+			current, exists = v, true
+		}
+	}
+	if !exists && !t.isTombstoned(k1, k2) && t.shadow != nil {
+		if v, err := t.shadow.Get(k1, k2); err == nil {
+			current, exists = v, true
+		}
+	}
+
+	if !exists || current != old {
+		return false
+	}
+
+	delete(t.table[k1], k2)
+	if t.shadow != nil {
+		t.tombstone(k1, k2)
+	}
+	return true
+}
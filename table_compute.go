@@ -0,0 +1,109 @@
+package structures
+
+// Compute atomically computes a new value for the given keys under the
+// write lock. If the keys are only visible through the shadow, the computed
+// value is written copy-on-write into the local table, leaving the shadow
+// untouched.
+func (t *builtinTable[K1, K2, V2]) Compute(k1 K1, k2 K2, fn func(oldVal V2, loaded bool) (newVal V2, del bool)) (actual V2, ok bool) {
+	t.Lock()
+	defer t.Unlock()
+
+	return t.computeLocked(k1, k2, fn)
+}
+
+// computeLocked is the body of Compute; the caller must already hold the write lock.
+func (t *builtinTable[K1, K2, V2]) computeLocked(k1 K1, k2 K2, fn func(oldVal V2, loaded bool) (newVal V2, del bool)) (actual V2, ok bool) {
+	var oldVal V2
+	loaded := false
+	if table2, exists := t.table[k1]; exists {
+		if v, exists := table2[k2]; exists {
+			oldVal, loaded = v, true
+		}
+	}
+	tombstoned := t.isTombstoned(k1, k2)
+	if !loaded && !tombstoned && t.shadow != nil {
+		if v, err := t.shadow.Get(k1, k2); err == nil {
+			oldVal, loaded = v, true
+		}
+	}
+
+	newVal, del := fn(oldVal, loaded)
+	if del {
+		if table2, exists := t.table[k1]; exists {
+			delete(table2, k2)
+		}
+		if t.shadow != nil {
+			t.tombstone(k1, k2)
+		}
+		return newVal, false
+	}
+
+	if _, exists := t.table[k1]; !exists {
+		t.table[k1] = make(map[K2]V2)
+	}
+	t.clearTombstone(k1, k2)
+	t.table[k1][k2] = newVal
+	return newVal, true
+}
+
+// LoadOrCompute returns the existing value for the given keys if one is
+// visible, otherwise it stores and returns the result of fn.
+func (t *builtinTable[K1, K2, V2]) LoadOrCompute(k1 K1, k2 K2, fn func() V2) (value V2, loaded bool) {
+	t.Lock()
+	defer t.Unlock()
+
+	return t.loadOrComputeLocked(k1, k2, fn)
+}
+
+// loadOrComputeLocked is the body of LoadOrCompute; the caller must already hold the write lock.
+func (t *builtinTable[K1, K2, V2]) loadOrComputeLocked(k1 K1, k2 K2, fn func() V2) (value V2, loaded bool) {
+	if table2, exists := t.table[k1]; exists {
+		if v, exists := table2[k2]; exists {
+			return v, true
+		}
+	}
+
+	if !t.isTombstoned(k1, k2) && t.shadow != nil {
+		if v, err := t.shadow.Get(k1, k2); err == nil {
+			return v, true
+		}
+	}
+
+	value = fn()
+	if _, exists := t.table[k1]; !exists {
+		t.table[k1] = make(map[K2]V2)
+	}
+	t.clearTombstone(k1, k2)
+	t.table[k1][k2] = value
+	return value, false
+}
+
+// Swap stores val as the new value for the given keys and returns the value
+// it replaced, if any.
+func (t *builtinTable[K1, K2, V2]) Swap(k1 K1, k2 K2, val V2) (previous V2, loaded bool) {
+	t.Lock()
+	defer t.Unlock()
+
+	return t.swapLocked(k1, k2, val)
+}
+
+// swapLocked is the body of Swap; the caller must already hold the write lock.
+func (t *builtinTable[K1, K2, V2]) swapLocked(k1 K1, k2 K2, val V2) (previous V2, loaded bool) {
+	if table2, exists := t.table[k1]; exists {
+		if v, exists := table2[k2]; exists {
+			previous, loaded = v, true
+		}
+	}
+	if !loaded && !t.isTombstoned(k1, k2) && t.shadow != nil {
+		if v, err := t.shadow.Get(k1, k2); err == nil {
+			previous, loaded = v, true
+		}
+	}
+
+	if _, exists := t.table[k1]; !exists {
+		t.table[k1] = make(map[K2]V2)
+	}
+	t.clearTombstone(k1, k2)
+	t.table[k1][k2] = val
+	return
+}
@@ -0,0 +1,126 @@
+package structures
+
+import "testing"
+
+func TestTableCompute(t *testing.T) {
+	tbl := NewTable[string, string, int]()
+
+	actual, ok := tbl.Compute("a", "x", func(oldVal int, loaded bool) (int, bool) {
+		if loaded {
+			t.Fatalf("Compute on a missing key pair should report loaded = false")
+		}
+		return 1, false
+	})
+	if !ok || actual != 1 {
+		t.Fatalf("Compute(a,x) = %d, %v, want 1, true", actual, ok)
+	}
+
+	_, ok = tbl.Compute("a", "x", func(oldVal int, loaded bool) (int, bool) {
+		if !loaded || oldVal != 1 {
+			t.Fatalf("Compute should see the previously stored value, got oldVal=%d loaded=%v", oldVal, loaded)
+		}
+		return 0, true
+	})
+	if ok {
+		t.Fatalf("Compute requesting delete should report ok = false")
+	}
+	if tbl.Contains("a", "x") {
+		t.Fatalf("key pair should be gone after Compute requested a delete")
+	}
+}
+
+func TestTableSwap(t *testing.T) {
+	tbl := NewTable[string, string, int]()
+
+	previous, loaded := tbl.Swap("a", "x", 1)
+	if loaded {
+		t.Fatalf("Swap on a missing key pair should report loaded = false, got previous=%d", previous)
+	}
+
+	previous, loaded = tbl.Swap("a", "x", 2)
+	if !loaded || previous != 1 {
+		t.Fatalf("Swap(a,x,2) = %d, %v, want 1, true", previous, loaded)
+	}
+}
+
+func TestTableShadowCopyCompute(t *testing.T) {
+	base := NewTable[string, string, int]()
+	_ = base.Set("a", "x", 1)
+
+	overlay := base.ShadowCopy()
+	actual, ok := overlay.Compute("a", "x", func(oldVal int, loaded bool) (int, bool) {
+		if !loaded || oldVal != 1 {
+			t.Fatalf("Compute should see the shadow's value, got oldVal=%d loaded=%v", oldVal, loaded)
+		}
+		return 10, false
+	})
+	if !ok || actual != 10 {
+		t.Fatalf("Compute(a,x) = %d, %v, want 10, true", actual, ok)
+	}
+
+	if v, _ := overlay.Get("a", "x"); v != 10 {
+		t.Fatalf("overlay.Get(a,x) = %d, want 10", v)
+	}
+	if v, _ := base.Get("a", "x"); v != 1 {
+		t.Fatalf("base should be unaffected by a Compute against the overlay, got a,x=%d", v)
+	}
+}
+
+func TestTableShadowCopySwap(t *testing.T) {
+	base := NewTable[string, string, int]()
+	_ = base.Set("a", "x", 1)
+
+	overlay := base.ShadowCopy()
+	previous, loaded := overlay.Swap("a", "x", 10)
+	if !loaded || previous != 1 {
+		t.Fatalf("Swap(a,x,10) = %d, %v, want 1, true (swap must see the shadow's value)", previous, loaded)
+	}
+
+	if v, _ := overlay.Get("a", "x"); v != 10 {
+		t.Fatalf("overlay.Get(a,x) = %d, want 10", v)
+	}
+	if v, _ := base.Get("a", "x"); v != 1 {
+		t.Fatalf("base should be unaffected by a Swap against the overlay, got a,x=%d", v)
+	}
+}
+
+func TestTableShadowCopyLoadOrCompute(t *testing.T) {
+	base := NewTable[string, string, int]()
+	_ = base.Set("a", "x", 1)
+
+	overlay := base.ShadowCopy()
+	value, loaded := overlay.LoadOrCompute("a", "x", func() int {
+		t.Fatalf("fn should not be called when a shadowed value already exists")
+		return 0
+	})
+	if !loaded || value != 1 {
+		t.Fatalf("LoadOrCompute(a,x) = %d, %v, want 1, true (must see the shadow's value)", value, loaded)
+	}
+
+	value, loaded = overlay.LoadOrCompute("b", "y", func() int { return 2 })
+	if loaded || value != 2 {
+		t.Fatalf("LoadOrCompute(b,y) = %d, %v, want 2, false", value, loaded)
+	}
+
+	if v, _ := overlay.Get("b", "y"); v != 2 {
+		t.Fatalf("overlay.Get(b,y) = %d, want 2", v)
+	}
+	if base.Contains("b", "y") {
+		t.Fatalf("base should be unaffected by a LoadOrCompute miss against the overlay")
+	}
+}
+
+func TestComparableTableCompareAndSwap(t *testing.T) {
+	tbl := NewComparableTable[string, string, int]()
+	_ = tbl.Set("a", "x", 1)
+
+	if tbl.CompareAndSwap("a", "x", 2, 3) {
+		t.Fatalf("CompareAndSwap should fail when old does not match the current value")
+	}
+	if !tbl.CompareAndSwap("a", "x", 1, 3) {
+		t.Fatalf("CompareAndSwap should succeed when old matches the current value")
+	}
+	if v, _ := tbl.Get("a", "x"); v != 3 {
+		t.Fatalf("Get(a,x) after CompareAndSwap = %d, want 3", v)
+	}
+}
@@ -0,0 +1,478 @@
+package structures
+
+import "errors"
+
+var (
+	// ErrDuplicateIndex is returned by Add when storing a value would
+	// collide with an existing entry under a unique index.
+	ErrDuplicateIndex = errors.New("value collides with an existing entry on a unique index")
+	// ErrIndexExists is returned by AddIndex when an index with the given name is already registered.
+	ErrIndexExists = errors.New("an index with that name is already registered")
+	// ErrIndexNotFound is returned by GetByIndex/DeleteByIndex for an unregistered index name.
+	ErrIndexNotFound = errors.New("no index registered with that name")
+)
+
+// tableIndexEntry is the set of key pairs currently stored under a single index key
+type tableIndexEntry[K1 comparable, K2 comparable] map[TableKey[K1, K2]]struct{}
+
+// tableIndex is a single secondary index registered on an IndexedTable
+type tableIndex[K1 comparable, K2 comparable, V any] struct {
+	extract func(V) any
+	unique  bool
+	entries map[any]tableIndexEntry[K1, K2]
+}
+
+// IndexedTable extends Table with secondary indexes derived from values, so
+// rows can be looked up by more than just their primary keys. Loading data
+// via JSON or gob decoding and ShadowCopy's Commit/Discard all route through
+// the same index-maintenance logic as Add/Set/Delete, so indexes never go
+// stale; ShadowCopy itself still returns a plain Table, since the returned
+// overlay has no indexes of its own until its writes are committed back.
+type IndexedTable[K1 comparable, K2 comparable, V any] interface {
+	Table[K1, K2, V]
+	// AddIndex registers a secondary index called name, deriving each row's
+	// index key by calling extract on its value. If unique is true, Add will
+	// fail with ErrDuplicateIndex when it would create a second row sharing
+	// an index key. AddIndex builds the index from the table's current
+	// contents and errors with ErrDuplicateIndex if that would violate
+	// uniqueness, or ErrIndexExists if name is already registered.
+	AddIndex(name string, extract func(V) any, unique bool) error
+	// GetByIndex returns every value currently stored under indexKey in the
+	// named index, or ErrIndexNotFound if no such index is registered.
+	GetByIndex(name string, indexKey any) ([]V, error)
+	// DeleteByIndex deletes every row currently stored under indexKey in the
+	// named index, returning how many rows were deleted, or ErrIndexNotFound
+	// if no such index is registered.
+	DeleteByIndex(name string, indexKey any) (int, error)
+}
+
+// indexedTable maintains secondary indexes on top of builtinTable, updating
+// them under the same write lock as every mutation.
+type indexedTable[K1 comparable, K2 comparable, V any] struct {
+	*builtinTable[K1, K2, V]
+	indexes map[string]*tableIndex[K1, K2, V]
+}
+
+// NewIndexedTable will create a new, empty instance of IndexedTable
+func NewIndexedTable[K1 comparable, K2 comparable, V any]() IndexedTable[K1, K2, V] {
+	return &indexedTable[K1, K2, V]{
+		builtinTable: NewTable[K1, K2, V]().(*builtinTable[K1, K2, V]),
+		indexes:      make(map[string]*tableIndex[K1, K2, V]),
+	}
+}
+
+// indexLocked adds k1/k2 to every registered index under val's extracted key.
+// The caller must already hold the write lock.
+func (t *indexedTable[K1, K2, V]) indexLocked(k1 K1, k2 K2, val V) {
+	tk := TableKey[K1, K2]{K1: k1, K2: k2}
+	for _, idx := range t.indexes {
+		key := idx.extract(val)
+		if idx.entries[key] == nil {
+			idx.entries[key] = make(tableIndexEntry[K1, K2])
+		}
+		idx.entries[key][tk] = struct{}{}
+	}
+}
+
+// unindexLocked removes k1/k2 from every registered index under oldVal's
+// extracted key. The caller must already hold the write lock.
+func (t *indexedTable[K1, K2, V]) unindexLocked(k1 K1, k2 K2, oldVal V) {
+	tk := TableKey[K1, K2]{K1: k1, K2: k2}
+	for _, idx := range t.indexes {
+		key := idx.extract(oldVal)
+		if entries, exists := idx.entries[key]; exists {
+			delete(entries, tk)
+			if len(entries) == 0 {
+				delete(idx.entries, key)
+			}
+		}
+	}
+}
+
+// checkUniqueLocked returns ErrDuplicateIndex if storing val would collide
+// with an existing row on any unique index. The caller must already hold the
+// write lock.
+func (t *indexedTable[K1, K2, V]) checkUniqueLocked(val V) error {
+	for _, idx := range t.indexes {
+		if !idx.unique {
+			continue
+		}
+		if entries, exists := idx.entries[idx.extract(val)]; exists && len(entries) > 0 {
+			return ErrDuplicateIndex
+		}
+	}
+	return nil
+}
+
+// uniqueCollisionLocked returns the value of a row that val would collide
+// with on a unique index, if any. The caller must already hold at least a
+// read lock.
+func (t *indexedTable[K1, K2, V]) uniqueCollisionLocked(val V) (value V, found bool) {
+	for _, idx := range t.indexes {
+		if !idx.unique {
+			continue
+		}
+		for tk := range idx.entries[idx.extract(val)] {
+			if v, exists := t.localLocked(tk.K1, tk.K2); exists {
+				return v, true
+			}
+		}
+	}
+	return value, false
+}
+
+// checkUniqueExcludingLocked returns ErrDuplicateIndex if storing val under
+// k1/k2 would collide with an existing row on a unique index, ignoring any
+// entry already indexed under k1/k2 itself. It is used by updates to an
+// existing row, which must not reject against their own, about-to-be-replaced
+// index entry. The caller must already hold the write lock.
+func (t *indexedTable[K1, K2, V]) checkUniqueExcludingLocked(k1 K1, k2 K2, val V) error {
+	exclude := TableKey[K1, K2]{K1: k1, K2: k2}
+	for _, idx := range t.indexes {
+		if !idx.unique {
+			continue
+		}
+		for tk := range idx.entries[idx.extract(val)] {
+			if tk != exclude {
+				return ErrDuplicateIndex
+			}
+		}
+	}
+	return nil
+}
+
+// localLocked returns the value currently stored locally for k1/k2, if any.
+// The caller must already hold at least a read lock.
+func (t *indexedTable[K1, K2, V]) localLocked(k1 K1, k2 K2) (value V, exists bool) {
+	if table2, ok := t.table[k1]; ok {
+		value, exists = table2[k2]
+	}
+	return
+}
+
+// Add stores a new value by the given keys, failing with ErrDuplicateIndex
+// if it would collide with an existing row on a unique index.
+func (t *indexedTable[K1, K2, V]) Add(k1 K1, k2 K2, newVal V) error {
+	t.Lock()
+	defer t.Unlock()
+
+	if err := t.checkUniqueLocked(newVal); err != nil {
+		return err
+	}
+
+	if err := t.addLocked(k1, k2, newVal); err != nil {
+		return err
+	}
+
+	t.indexLocked(k1, k2, newVal)
+	return nil
+}
+
+// Set stores a new value by the given keys, re-indexing it on every
+// registered index, or returns ErrDuplicateIndex without storing if doing so
+// would collide with a different row on a unique index.
+func (t *indexedTable[K1, K2, V]) Set(k1 K1, k2 K2, newVal V) error {
+	t.Lock()
+	defer t.Unlock()
+
+	if err := t.checkUniqueExcludingLocked(k1, k2, newVal); err != nil {
+		return err
+	}
+
+	if old, exists := t.localLocked(k1, k2); exists {
+		t.unindexLocked(k1, k2, old)
+	}
+
+	if err := t.setLocked(k1, k2, newVal); err != nil {
+		return err
+	}
+
+	t.indexLocked(k1, k2, newVal)
+	return nil
+}
+
+// GetOrSet returns the value by its keys, indexing the stored value if the
+// keys did not exist before. If the keys did not exist and newVal would
+// collide with an existing row on a unique index, the existing value for a
+// colliding row is returned instead and newVal is not stored.
+func (t *indexedTable[K1, K2, V]) GetOrSet(k1 K1, k2 K2, newVal V) V {
+	t.Lock()
+	defer t.Unlock()
+
+	if old, existedBefore := t.localLocked(k1, k2); existedBefore {
+		return old
+	}
+
+	if existing, collides := t.uniqueCollisionLocked(newVal); collides {
+		return existing
+	}
+
+	value := t.getOrSetLocked(k1, k2, newVal)
+
+	if _, existsNow := t.localLocked(k1, k2); existsNow {
+		t.indexLocked(k1, k2, value)
+	}
+
+	return value
+}
+
+// Delete deletes the value by its keys, removing it from every registered
+// index.
+func (t *indexedTable[K1, K2, V]) Delete(k1 K1, k2 K2) error {
+	t.Lock()
+	defer t.Unlock()
+
+	old, hadOld := t.localLocked(k1, k2)
+
+	if err := t.deleteLocked(k1, k2); err != nil {
+		return err
+	}
+
+	if hadOld {
+		t.unindexLocked(k1, k2, old)
+	}
+	return nil
+}
+
+// Compute atomically computes a new value for the given keys under the
+// write lock, re-indexing the row on every registered index. If fn's
+// non-delete result would collide with a different row on a unique index,
+// the result is rejected and the row is left exactly as fn found it.
+func (t *indexedTable[K1, K2, V]) Compute(k1 K1, k2 K2, fn func(oldVal V, loaded bool) (newVal V, del bool)) (actual V, ok bool) {
+	t.Lock()
+	defer t.Unlock()
+
+	old, hadOld := t.localLocked(k1, k2)
+
+	actual, ok = t.computeLocked(k1, k2, func(oldVal V, loaded bool) (V, bool) {
+		newVal, del := fn(oldVal, loaded)
+		if !del && t.checkUniqueExcludingLocked(k1, k2, newVal) != nil {
+			return oldVal, !loaded
+		}
+		return newVal, del
+	})
+
+	if hadOld {
+		t.unindexLocked(k1, k2, old)
+	}
+	if ok {
+		t.indexLocked(k1, k2, actual)
+	}
+	return actual, ok
+}
+
+// LoadOrCompute returns the existing value for the given keys if one is
+// visible, otherwise it stores and returns the result of fn, indexing the
+// stored value if it was newly inserted.
+func (t *indexedTable[K1, K2, V]) LoadOrCompute(k1 K1, k2 K2, fn func() V) (value V, loaded bool) {
+	t.Lock()
+	defer t.Unlock()
+
+	value, loaded = t.loadOrComputeLocked(k1, k2, fn)
+
+	if !loaded {
+		t.indexLocked(k1, k2, value)
+	}
+	return value, loaded
+}
+
+// Swap stores val as the new value for the given keys and returns the value
+// it replaced, if any, re-indexing the row on every registered index. If val
+// would collide with a different row on a unique index, the swap is refused
+// and the row's unchanged previous value is returned instead.
+func (t *indexedTable[K1, K2, V]) Swap(k1 K1, k2 K2, val V) (previous V, loaded bool) {
+	t.Lock()
+	defer t.Unlock()
+
+	old, hadOld := t.localLocked(k1, k2)
+	if t.checkUniqueExcludingLocked(k1, k2, val) != nil {
+		return old, hadOld
+	}
+
+	if hadOld {
+		t.unindexLocked(k1, k2, old)
+	}
+
+	previous, loaded = t.swapLocked(k1, k2, val)
+
+	t.indexLocked(k1, k2, val)
+	return previous, loaded
+}
+
+// AddIndex registers a secondary index called name, deriving each row's
+// index key by calling extract on its value, and builds it from the
+// table's current contents.
+func (t *indexedTable[K1, K2, V]) AddIndex(name string, extract func(V) any, unique bool) error {
+	t.Lock()
+	defer t.Unlock()
+
+	if _, exists := t.indexes[name]; exists {
+		return ErrIndexExists
+	}
+
+	idx := &tableIndex[K1, K2, V]{
+		extract: extract,
+		unique:  unique,
+		entries: make(map[any]tableIndexEntry[K1, K2]),
+	}
+
+	var buildErr error
+	t.rangeLocked(func(k1 K1, k2 K2, val V) bool {
+		key := extract(val)
+		if unique {
+			if entries, exists := idx.entries[key]; exists && len(entries) > 0 {
+				buildErr = ErrDuplicateIndex
+				return false
+			}
+		}
+		if idx.entries[key] == nil {
+			idx.entries[key] = make(tableIndexEntry[K1, K2])
+		}
+		idx.entries[key][TableKey[K1, K2]{K1: k1, K2: k2}] = struct{}{}
+		return true
+	})
+	if buildErr != nil {
+		return buildErr
+	}
+
+	t.indexes[name] = idx
+	return nil
+}
+
+// GetByIndex returns every value currently stored under indexKey in the
+// named index.
+func (t *indexedTable[K1, K2, V]) GetByIndex(name string, indexKey any) ([]V, error) {
+	t.RLock()
+	defer t.RUnlock()
+
+	idx, exists := t.indexes[name]
+	if !exists {
+		return nil, ErrIndexNotFound
+	}
+
+	entries := idx.entries[indexKey]
+	values := make([]V, 0, len(entries))
+	for tk := range entries {
+		if v, exists := t.localLocked(tk.K1, tk.K2); exists {
+			values = append(values, v)
+		}
+	}
+	return values, nil
+}
+
+// DeleteByIndex deletes every row currently stored under indexKey in the
+// named index, returning how many rows were deleted.
+func (t *indexedTable[K1, K2, V]) DeleteByIndex(name string, indexKey any) (int, error) {
+	t.Lock()
+	defer t.Unlock()
+
+	idx, exists := t.indexes[name]
+	if !exists {
+		return 0, ErrIndexNotFound
+	}
+
+	keys := make([]TableKey[K1, K2], 0, len(idx.entries[indexKey]))
+	for tk := range idx.entries[indexKey] {
+		keys = append(keys, tk)
+	}
+
+	deleted := 0
+	for _, tk := range keys {
+		old, hadOld := t.localLocked(tk.K1, tk.K2)
+		if err := t.deleteLocked(tk.K1, tk.K2); err != nil {
+			continue
+		}
+		deleted++
+		if hadOld {
+			t.unindexLocked(tk.K1, tk.K2, old)
+		}
+	}
+	return deleted, nil
+}
+
+// reindexAllLocked rebuilds every registered index from the table's current
+// contents, the same way AddIndex builds a new one, leaving the existing
+// indexes untouched if any of them would come out violating uniqueness. The
+// caller must already hold the write lock.
+func (t *indexedTable[K1, K2, V]) reindexAllLocked() error {
+	fresh := make(map[string]*tableIndex[K1, K2, V], len(t.indexes))
+	for name, idx := range t.indexes {
+		fresh[name] = &tableIndex[K1, K2, V]{
+			extract: idx.extract,
+			unique:  idx.unique,
+			entries: make(map[any]tableIndexEntry[K1, K2]),
+		}
+	}
+
+	var buildErr error
+	t.rangeLocked(func(k1 K1, k2 K2, val V) bool {
+		for _, idx := range fresh {
+			key := idx.extract(val)
+			if idx.unique {
+				if entries, exists := idx.entries[key]; exists && len(entries) > 0 {
+					buildErr = ErrDuplicateIndex
+					return false
+				}
+			}
+			if idx.entries[key] == nil {
+				idx.entries[key] = make(tableIndexEntry[K1, K2])
+			}
+			idx.entries[key][TableKey[K1, K2]{K1: k1, K2: k2}] = struct{}{}
+		}
+		return true
+	})
+	if buildErr != nil {
+		return buildErr
+	}
+
+	t.indexes = fresh
+	return nil
+}
+
+// UnmarshalJSON replaces the table's contents the same way
+// builtinTable.UnmarshalJSON does, then rebuilds every registered index from
+// the newly loaded contents so GetByIndex/DeleteByIndex stay in sync.
+func (t *indexedTable[K1, K2, V]) UnmarshalJSON(data []byte) error {
+	if err := t.builtinTable.UnmarshalJSON(data); err != nil {
+		return err
+	}
+
+	t.Lock()
+	defer t.Unlock()
+	return t.reindexAllLocked()
+}
+
+// GobDecode replaces the table's contents the same way
+// builtinTable.GobDecode does, then rebuilds every registered index from the
+// newly loaded contents so GetByIndex/DeleteByIndex stay in sync.
+func (t *indexedTable[K1, K2, V]) GobDecode(data []byte) error {
+	if err := t.builtinTable.GobDecode(data); err != nil {
+		return err
+	}
+
+	t.Lock()
+	defer t.Unlock()
+	return t.reindexAllLocked()
+}
+
+// Discard empties the local layer the same way builtinTable.Discard does,
+// then clears every registered index to match the now-empty table.
+func (t *indexedTable[K1, K2, V]) Discard() {
+	t.Lock()
+	defer t.Unlock()
+
+	t.discardLocked()
+	for _, idx := range t.indexes {
+		idx.entries = make(map[any]tableIndexEntry[K1, K2])
+	}
+}
+
+// ShadowCopy returns a new table with this indexed table as its shadow. The
+// returned table is a plain Table, not an IndexedTable: values written into
+// it are not indexed until Commit merges them back into this table via Set
+// and Delete, which re-index them as usual.
+func (t *indexedTable[K1, K2, V]) ShadowCopy() ShadowedTable[K1, K2, V] {
+	newTable := NewTable[K1, K2, V]()
+	newTable.(*builtinTable[K1, K2, V]).shadow = t
+	return newTable
+}
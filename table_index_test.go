@@ -0,0 +1,239 @@
+package structures
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestIndexedTableSwapReindexes(t *testing.T) {
+	it := NewIndexedTable[string, string, int]()
+	if err := it.AddIndex("byVal", func(v int) any { return v }, false); err != nil {
+		t.Fatalf("AddIndex() error = %v", err)
+	}
+
+	if err := it.Add("a", "x", 1); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+
+	it.Swap("a", "x", 2)
+
+	if vals, err := it.GetByIndex("byVal", 1); err != nil || len(vals) != 0 {
+		t.Fatalf("GetByIndex(byVal, 1) = %v, %v, want empty, nil (old value must be unindexed)", vals, err)
+	}
+	if vals, err := it.GetByIndex("byVal", 2); err != nil || len(vals) != 1 || vals[0] != 2 {
+		t.Fatalf("GetByIndex(byVal, 2) = %v, %v, want [2], nil", vals, err)
+	}
+}
+
+func TestIndexedTableGetOrSetEnforcesUniqueness(t *testing.T) {
+	it := NewIndexedTable[string, string, int]()
+	if err := it.AddIndex("byVal", func(v int) any { return v }, true); err != nil {
+		t.Fatalf("AddIndex() error = %v", err)
+	}
+
+	if err := it.Add("a", "x", 1); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+
+	got := it.GetOrSet("b", "y", 1)
+	if got != 1 {
+		t.Fatalf("GetOrSet(b,y,1) = %d, want 1 (existing unique row)", got)
+	}
+	if it.Contains("b", "y") {
+		t.Fatalf("GetOrSet must not store a value colliding with a unique index")
+	}
+
+	vals, err := it.GetByIndex("byVal", 1)
+	if err != nil || len(vals) != 1 {
+		t.Fatalf("GetByIndex(byVal, 1) = %v, %v, want exactly one row", vals, err)
+	}
+}
+
+func TestIndexedTableComputeReindexes(t *testing.T) {
+	it := NewIndexedTable[string, string, int]()
+	if err := it.AddIndex("byVal", func(v int) any { return v }, false); err != nil {
+		t.Fatalf("AddIndex() error = %v", err)
+	}
+
+	if err := it.Add("a", "x", 1); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+
+	it.Compute("a", "x", func(oldVal int, loaded bool) (int, bool) {
+		return oldVal + 1, false
+	})
+
+	if vals, _ := it.GetByIndex("byVal", 1); len(vals) != 0 {
+		t.Fatalf("GetByIndex(byVal, 1) = %v, want empty after Compute changed the value", vals)
+	}
+	if vals, _ := it.GetByIndex("byVal", 2); len(vals) != 1 {
+		t.Fatalf("GetByIndex(byVal, 2) = %v, want [2] after Compute", vals)
+	}
+}
+
+func TestIndexedTableSetEnforcesUniqueness(t *testing.T) {
+	it := NewIndexedTable[string, string, int]()
+	if err := it.AddIndex("byVal", func(v int) any { return v }, true); err != nil {
+		t.Fatalf("AddIndex() error = %v", err)
+	}
+
+	if err := it.Add("a", "x", 1); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+
+	if err := it.Set("b", "y", 1); err != ErrDuplicateIndex {
+		t.Fatalf("Set(b,y,1) error = %v, want ErrDuplicateIndex", err)
+	}
+	if it.Contains("b", "y") {
+		t.Fatalf("Set must not store a value colliding with a unique index")
+	}
+
+	if err := it.Set("a", "x", 1); err != nil {
+		t.Fatalf("Set(a,x,1) overwriting its own row with the same value should succeed, got %v", err)
+	}
+}
+
+func TestIndexedTableSwapEnforcesUniqueness(t *testing.T) {
+	it := NewIndexedTable[string, string, int]()
+	if err := it.AddIndex("byVal", func(v int) any { return v }, true); err != nil {
+		t.Fatalf("AddIndex() error = %v", err)
+	}
+
+	if err := it.Add("a", "x", 1); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	if err := it.Add("b", "y", 2); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+
+	previous, loaded := it.Swap("b", "y", 1)
+	if !loaded || previous != 2 {
+		t.Fatalf("Swap(b,y,1) = %d, %v, want 2, true (swap must be refused, row left unchanged)", previous, loaded)
+	}
+	if v, _ := it.Get("b", "y"); v != 2 {
+		t.Fatalf("Get(b,y) after refused Swap = %d, want 2", v)
+	}
+
+	vals, err := it.GetByIndex("byVal", 1)
+	if err != nil || len(vals) != 1 {
+		t.Fatalf("GetByIndex(byVal, 1) = %v, %v, want exactly one row", vals, err)
+	}
+}
+
+func TestIndexedTableComputeEnforcesUniqueness(t *testing.T) {
+	it := NewIndexedTable[string, string, int]()
+	if err := it.AddIndex("byVal", func(v int) any { return v }, true); err != nil {
+		t.Fatalf("AddIndex() error = %v", err)
+	}
+
+	if err := it.Add("a", "x", 1); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	if err := it.Add("b", "y", 2); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+
+	actual, ok := it.Compute("b", "y", func(oldVal int, loaded bool) (int, bool) {
+		return 1, false
+	})
+	if !ok || actual != 2 {
+		t.Fatalf("Compute(b,y) = %d, %v, want 2, true (collision must be rejected, row left unchanged)", actual, ok)
+	}
+
+	vals, err := it.GetByIndex("byVal", 1)
+	if err != nil || len(vals) != 1 {
+		t.Fatalf("GetByIndex(byVal, 1) = %v, %v, want exactly one row", vals, err)
+	}
+}
+
+func TestIndexedTableLoadOrComputeIndexesNewRows(t *testing.T) {
+	it := NewIndexedTable[string, string, int]()
+	if err := it.AddIndex("byVal", func(v int) any { return v }, false); err != nil {
+		t.Fatalf("AddIndex() error = %v", err)
+	}
+
+	it.LoadOrCompute("a", "x", func() int { return 5 })
+
+	if vals, err := it.GetByIndex("byVal", 5); err != nil || len(vals) != 1 {
+		t.Fatalf("GetByIndex(byVal, 5) = %v, %v, want [5]", vals, err)
+	}
+}
+
+func TestIndexedTableLoadTableJSONRebuildsIndexes(t *testing.T) {
+	it := NewIndexedTable[string, string, int]()
+	if err := it.AddIndex("byVal", func(v int) any { return v }, false); err != nil {
+		t.Fatalf("AddIndex() error = %v", err)
+	}
+	if err := it.Add("a", "x", 1); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := DumpTableJSON[string, string, int](&buf, it); err != nil {
+		t.Fatalf("DumpTableJSON() error = %v", err)
+	}
+
+	replacement := NewTable[string, string, int]()
+	_ = replacement.Set("b", "y", 2)
+	var replacementBuf bytes.Buffer
+	if err := DumpTableJSON[string, string, int](&replacementBuf, replacement); err != nil {
+		t.Fatalf("DumpTableJSON() error = %v", err)
+	}
+
+	if err := LoadTableJSON[string, string, int](&replacementBuf, it); err != nil {
+		t.Fatalf("LoadTableJSON() error = %v", err)
+	}
+
+	if vals, _ := it.GetByIndex("byVal", 1); len(vals) != 0 {
+		t.Fatalf("GetByIndex(byVal, 1) = %v, want empty after LoadTableJSON replaced the table", vals)
+	}
+	if vals, err := it.GetByIndex("byVal", 2); err != nil || len(vals) != 1 {
+		t.Fatalf("GetByIndex(byVal, 2) = %v, %v, want [2] after LoadTableJSON", vals, err)
+	}
+}
+
+func TestIndexedTableDiscardClearsIndexes(t *testing.T) {
+	it := NewIndexedTable[string, string, int]()
+	if err := it.AddIndex("byVal", func(v int) any { return v }, false); err != nil {
+		t.Fatalf("AddIndex() error = %v", err)
+	}
+	if err := it.Add("a", "x", 1); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+
+	shadowed, ok := it.(ShadowedTable[string, string, int])
+	if !ok {
+		t.Fatalf("indexedTable must still satisfy ShadowedTable")
+	}
+	shadowed.Discard()
+
+	if vals, _ := it.GetByIndex("byVal", 1); len(vals) != 0 {
+		t.Fatalf("GetByIndex(byVal, 1) = %v, want empty after Discard", vals)
+	}
+}
+
+func TestIndexedTableShadowCopyCommitReindexes(t *testing.T) {
+	it := NewIndexedTable[string, string, int]()
+	if err := it.AddIndex("byVal", func(v int) any { return v }, true); err != nil {
+		t.Fatalf("AddIndex() error = %v", err)
+	}
+	if err := it.Add("a", "x", 1); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+
+	shadowed, ok := it.(ShadowedTable[string, string, int])
+	if !ok {
+		t.Fatalf("indexedTable must still satisfy ShadowedTable")
+	}
+
+	overlay := shadowed.ShadowCopy()
+	_ = overlay.Set("b", "y", 2)
+	if err := overlay.Commit(); err != nil {
+		t.Fatalf("Commit() error = %v", err)
+	}
+
+	vals, err := it.GetByIndex("byVal", 2)
+	if err != nil || len(vals) != 1 {
+		t.Fatalf("GetByIndex(byVal, 2) = %v, %v, want [2] after committing the overlay", vals, err)
+	}
+}
@@ -0,0 +1,45 @@
+package structures
+
+import "testing"
+
+func TestTableRangeKeysValuesLen(t *testing.T) {
+	tbl := NewTable[string, string, int]()
+	_ = tbl.Set("a", "x", 1)
+	_ = tbl.Set("a", "y", 2)
+	_ = tbl.Set("b", "x", 3)
+
+	if tbl.Len() != 3 {
+		t.Fatalf("Len() = %d, want 3", tbl.Len())
+	}
+
+	count := 0
+	tbl.Range(func(k1, k2 string, val int) bool {
+		count++
+		return true
+	})
+	if count != 3 {
+		t.Fatalf("Range visited %d entries, want 3", count)
+	}
+
+	if len(tbl.Keys()) != 3 {
+		t.Fatalf("Keys() = %v, want 3 entries", tbl.Keys())
+	}
+	if len(tbl.Values()) != 3 {
+		t.Fatalf("Values() = %v, want 3 entries", tbl.Values())
+	}
+}
+
+func TestTableRangeStopsEarly(t *testing.T) {
+	tbl := NewTable[string, string, int]()
+	_ = tbl.Set("a", "x", 1)
+	_ = tbl.Set("a", "y", 2)
+
+	visited := 0
+	tbl.Range(func(k1, k2 string, val int) bool {
+		visited++
+		return false
+	})
+	if visited != 1 {
+		t.Fatalf("Range visited %d entries, want 1 after returning false", visited)
+	}
+}
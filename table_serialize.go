@@ -0,0 +1,160 @@
+package structures
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// tableJSONEntry is the array-form JSON representation of a single Table
+// entry, used when K1 and K2 are not both string or integer types.
+type tableJSONEntry[K1 comparable, K2 comparable, V any] struct {
+	K1 K1 `json:"k1"`
+	K2 K2 `json:"k2"`
+	V  V  `json:"v"`
+}
+
+// MarshalJSON flattens the shadow chain via ToMap and encodes the result.
+// If K1 and K2 are both string or integer types, the result is a nested
+// JSON object keyed by their stringified values; otherwise (including
+// floating-point keys) it is an array of {"k1":..,"k2":..,"v":..} entries.
+func (t *builtinTable[K1, K2, V]) MarshalJSON() ([]byte, error) {
+	m := t.ToMap()
+
+	if useObjectForm[K1]() && useObjectForm[K2]() {
+		obj := make(map[string]map[string]V, len(m))
+		for k1, table2 := range m {
+			inner := make(map[string]V, len(table2))
+			for k2, v := range table2 {
+				inner[fmt.Sprint(k2)] = v
+			}
+			obj[fmt.Sprint(k1)] = inner
+		}
+		return json.Marshal(obj)
+	}
+
+	entries := make([]tableJSONEntry[K1, K2, V], 0)
+	for k1, table2 := range m {
+		for k2, v := range table2 {
+			entries = append(entries, tableJSONEntry[K1, K2, V]{K1: k1, K2: k2, V: v})
+		}
+	}
+	return json.Marshal(entries)
+}
+
+// UnmarshalJSON accepts either JSON form produced by MarshalJSON and
+// replaces the table's local contents, dropping any shadow and tombstones.
+func (t *builtinTable[K1, K2, V]) UnmarshalJSON(data []byte) error {
+	trimmed := bytes.TrimLeft(data, " \t\r\n")
+	if len(trimmed) == 0 {
+		return nil
+	}
+
+	table := make(map[K1]map[K2]V)
+
+	switch trimmed[0] {
+	case '{':
+		var obj map[string]map[string]V
+		if err := json.Unmarshal(data, &obj); err != nil {
+			return err
+		}
+		for k1Str, inner := range obj {
+			k1, err := parseJSONKey[K1](k1Str)
+			if err != nil {
+				return err
+			}
+			innerTable := make(map[K2]V, len(inner))
+			for k2Str, v := range inner {
+				k2, err := parseJSONKey[K2](k2Str)
+				if err != nil {
+					return err
+				}
+				innerTable[k2] = v
+			}
+			table[k1] = innerTable
+		}
+	case '[':
+		var entries []tableJSONEntry[K1, K2, V]
+		if err := json.Unmarshal(data, &entries); err != nil {
+			return err
+		}
+		for _, e := range entries {
+			if _, exists := table[e.K1]; !exists {
+				table[e.K1] = make(map[K2]V)
+			}
+			table[e.K1][e.K2] = e.V
+		}
+	default:
+		return errors.New("structures: invalid JSON payload for table")
+	}
+
+	t.Lock()
+	defer t.Unlock()
+
+	t.table = table
+	t.tombstones = make(map[K1]map[K2]struct{})
+	t.shadow = nil
+	return nil
+}
+
+// GobEncode flattens the shadow chain via ToMap and gob-encodes the result.
+func (t *builtinTable[K1, K2, V]) GobEncode() ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(t.ToMap()); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// GobDecode replaces the table's local contents with the decoded data,
+// dropping any shadow and tombstones.
+func (t *builtinTable[K1, K2, V]) GobDecode(data []byte) error {
+	var table map[K1]map[K2]V
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&table); err != nil {
+		return err
+	}
+
+	t.Lock()
+	defer t.Unlock()
+
+	t.table = table
+	t.tombstones = make(map[K1]map[K2]struct{})
+	t.shadow = nil
+	return nil
+}
+
+// DumpTableJSON writes t's JSON encoding to w. t must implement
+// json.Marshaler (as tables constructed with NewTable do); otherwise
+// DumpTableJSON errors rather than silently encoding t's unexported fields
+// as "{}".
+func DumpTableJSON[K1 comparable, K2 comparable, V any](w io.Writer, t Table[K1, K2, V]) error {
+	if _, ok := t.(json.Marshaler); !ok {
+		return fmt.Errorf("structures: %T does not support JSON serialization", t)
+	}
+
+	data, err := json.Marshal(t)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+// LoadTableJSON reads a JSON encoding produced by DumpTableJSON from r and
+// replaces t's contents. t must implement json.Unmarshaler (as tables
+// constructed with NewTable do); otherwise LoadTableJSON errors rather than
+// silently leaving t unchanged.
+func LoadTableJSON[K1 comparable, K2 comparable, V any](r io.Reader, t Table[K1, K2, V]) error {
+	if _, ok := t.(json.Unmarshaler); !ok {
+		return fmt.Errorf("structures: %T does not support JSON deserialization", t)
+	}
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, t)
+}
@@ -0,0 +1,34 @@
+package structures
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestDumpTableJSONRejectsUnsupportedTable(t *testing.T) {
+	tbl := NewShardedTable[string, string, int](4)
+	_ = tbl.Set("a", "x", 1)
+
+	var buf bytes.Buffer
+	if err := DumpTableJSON[string, string, int](&buf, tbl); err == nil {
+		t.Fatalf("DumpTableJSON on a shardedTable should error instead of silently writing %q", buf.String())
+	}
+}
+
+func TestDumpTableJSONRoundTripsBuiltinTable(t *testing.T) {
+	tbl := NewTable[string, string, int]()
+	_ = tbl.Set("a", "x", 1)
+
+	var buf bytes.Buffer
+	if err := DumpTableJSON[string, string, int](&buf, tbl); err != nil {
+		t.Fatalf("DumpTableJSON() error = %v", err)
+	}
+
+	loaded := NewTable[string, string, int]()
+	if err := LoadTableJSON[string, string, int](&buf, loaded); err != nil {
+		t.Fatalf("LoadTableJSON() error = %v", err)
+	}
+	if v, err := loaded.Get("a", "x"); err != nil || v != 1 {
+		t.Fatalf("loaded.Get(a,x) = %d, %v, want 1, nil", v, err)
+	}
+}
@@ -0,0 +1,52 @@
+package structures
+
+import "testing"
+
+func TestTableShadowCopyCommit(t *testing.T) {
+	base := NewTable[string, string, int]()
+	_ = base.Set("a", "x", 1)
+	_ = base.Set("b", "y", 2)
+
+	overlay := base.ShadowCopy()
+	_ = overlay.Set("a", "x", 10)
+	_ = overlay.Delete("b", "y")
+
+	if v, err := overlay.Get("a", "x"); err != nil || v != 10 {
+		t.Fatalf("Get(a,x) through overlay = %d, %v, want 10, nil", v, err)
+	}
+	if overlay.Contains("b", "y") {
+		t.Fatalf("overlay should not see b,y after local delete")
+	}
+
+	if err := overlay.Commit(); err != nil {
+		t.Fatalf("Commit() error = %v", err)
+	}
+
+	if v, _ := base.Get("a", "x"); v != 10 {
+		t.Fatalf("base.Get(a,x) after Commit = %d, want 10", v)
+	}
+	if base.Contains("b", "y") {
+		t.Fatalf("base should have dropped b,y after Commit")
+	}
+}
+
+func TestTableShadowCopyDiscard(t *testing.T) {
+	base := NewTable[string, string, int]()
+	_ = base.Set("a", "x", 1)
+
+	overlay := base.ShadowCopy()
+	_ = overlay.Set("a", "x", 99)
+
+	overlay.Discard()
+
+	if v, _ := overlay.Get("a", "x"); v != 1 {
+		t.Fatalf("overlay.Get(a,x) after Discard = %d, want 1 (reads through to shadow)", v)
+	}
+}
+
+func TestTableCommitWithoutShadowErrors(t *testing.T) {
+	tbl := NewTable[string, string, int]()
+	if err := tbl.Commit(); err == nil {
+		t.Fatalf("Commit() on a table with no shadow should error")
+	}
+}
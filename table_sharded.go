@@ -0,0 +1,180 @@
+package structures
+
+// TableHasher produces a shard-selection hash for a key pair (k1, k2). It
+// only needs to distribute keys evenly; it is not used for equality checks.
+type TableHasher[K1 comparable, K2 comparable] func(k1 K1, k2 K2) uint64
+
+// defaultTableHasher combines the default hashers for K1 and K2 using an
+// FNV-style mix so that shard assignment depends on both keys.
+func defaultTableHasher[K1 comparable, K2 comparable]() TableHasher[K1, K2] {
+	h1 := defaultHasher[K1]()
+	h2 := defaultHasher[K2]()
+	return func(k1 K1, k2 K2) uint64 {
+		const prime = 1099511628211
+		return h1(k1)*prime ^ h2(k2)
+	}
+}
+
+// shardedTable stripes its contents across a fixed number of builtinTable
+// shards, each guarded by its own RWMutex, so that a write to one shard
+// never blocks reads or writes against another.
+//
+// shardedTable does not support the ShadowedTable overlay operations
+// (ShadowCopy, Commit, Discard, Flatten): a shadow copy of a sharded table
+// would need one shadow per shard, sharing the parent's shard count and hash
+// function, and Commit would need to merge each shard independently under
+// its own lock. NewShardedTable does not build that plumbing, so it returns
+// a plain Table[K1,K2,V] rather than a ShadowedTable[K1,K2,V].
+type shardedTable[K1 comparable, K2 comparable, V any] struct {
+	shards []*builtinTable[K1, K2, V]
+	mask   uint64
+	hasher TableHasher[K1, K2]
+}
+
+// NewShardedTable creates a Table that stripes its contents across shards
+// shards to remove the single-RWMutex bottleneck of NewTable under mixed
+// read/write workloads. shards is rounded up to the next power of two; if
+// shards <= 0, DefaultShardCount is used. An optional TableHasher may be
+// supplied to control shard assignment for key types defaultHasher does not
+// know about.
+func NewShardedTable[K1 comparable, K2 comparable, V any](shards int, hasher ...TableHasher[K1, K2]) Table[K1, K2, V] {
+	if shards <= 0 {
+		shards = DefaultShardCount()
+	}
+	shards = nextPowerOfTwo(shards)
+
+	h := defaultTableHasher[K1, K2]()
+	if len(hasher) > 0 && hasher[0] != nil {
+		h = hasher[0]
+	}
+
+	t := &shardedTable[K1, K2, V]{
+		shards: make([]*builtinTable[K1, K2, V], shards),
+		mask:   uint64(shards - 1),
+		hasher: h,
+	}
+	for i := range t.shards {
+		t.shards[i] = NewTable[K1, K2, V]().(*builtinTable[K1, K2, V])
+	}
+	return t
+}
+
+// shardFor returns the shard that owns the key pair (k1, k2)
+func (t *shardedTable[K1, K2, V]) shardFor(k1 K1, k2 K2) *builtinTable[K1, K2, V] {
+	return t.shards[t.hasher(k1, k2)&t.mask]
+}
+
+// Add stores a new value by the given keys or will error if the keys already exists
+func (t *shardedTable[K1, K2, V]) Add(k1 K1, k2 K2, newVal V) error {
+	return t.shardFor(k1, k2).Add(k1, k2, newVal)
+}
+
+// Contains returns whether a value exists for the given keys
+func (t *shardedTable[K1, K2, V]) Contains(k1 K1, k2 K2) bool {
+	return t.shardFor(k1, k2).Contains(k1, k2)
+}
+
+// Delete deletes the value by its keys, if the keys does not exist an error will be returned.
+func (t *shardedTable[K1, K2, V]) Delete(k1 K1, k2 K2) error {
+	return t.shardFor(k1, k2).Delete(k1, k2)
+}
+
+// Get returns the value by its keys, if the keys does not exist an error will be returned.
+func (t *shardedTable[K1, K2, V]) Get(k1 K1, k2 K2) (V, error) {
+	return t.shardFor(k1, k2).Get(k1, k2)
+}
+
+// GetOrDefault returns the value by its keys, if the keys does not exist a given default will be returned
+func (t *shardedTable[K1, K2, V]) GetOrDefault(k1 K1, k2 K2, def V) V {
+	return t.shardFor(k1, k2).GetOrDefault(k1, k2, def)
+}
+
+// GetOrSet returns the value by its keys, if the keys does not exist, the given value will be set for them
+func (t *shardedTable[K1, K2, V]) GetOrSet(k1 K1, k2 K2, newVal V) V {
+	return t.shardFor(k1, k2).GetOrSet(k1, k2, newVal)
+}
+
+// Set stores a new value by the given keys
+func (t *shardedTable[K1, K2, V]) Set(k1 K1, k2 K2, newVal V) error {
+	return t.shardFor(k1, k2).Set(k1, k2, newVal)
+}
+
+// ToMap converts the table instance to a native map
+func (t *shardedTable[K1, K2, V]) ToMap() map[K1]map[K2]V {
+	result := make(map[K1]map[K2]V)
+	for _, shard := range t.shards {
+		for k1, table2 := range shard.ToMap() {
+			if _, exists := result[k1]; !exists {
+				result[k1] = make(map[K2]V, len(table2))
+			}
+			for k2, v := range table2 {
+				result[k1][k2] = v
+			}
+		}
+	}
+	return result
+}
+
+// Range iterates over every key/value pair across all shards, invoking fn
+// for each of them. Iteration stops early if fn returns false.
+func (t *shardedTable[K1, K2, V]) Range(fn func(k1 K1, k2 K2, val V) bool) {
+	for _, shard := range t.shards {
+		stopped := false
+		shard.Range(func(k1 K1, k2 K2, val V) bool {
+			if !fn(k1, k2, val) {
+				stopped = true
+				return false
+			}
+			return true
+		})
+		if stopped {
+			return
+		}
+	}
+}
+
+// Keys returns all key pairs currently visible in the table
+func (t *shardedTable[K1, K2, V]) Keys() []TableKey[K1, K2] {
+	keys := make([]TableKey[K1, K2], 0, t.Len())
+	t.Range(func(k1 K1, k2 K2, _ V) bool {
+		keys = append(keys, TableKey[K1, K2]{K1: k1, K2: k2})
+		return true
+	})
+	return keys
+}
+
+// Values returns all values currently visible in the table
+func (t *shardedTable[K1, K2, V]) Values() []V {
+	values := make([]V, 0, t.Len())
+	t.Range(func(_ K1, _ K2, val V) bool {
+		values = append(values, val)
+		return true
+	})
+	return values
+}
+
+// Compute atomically computes a new value for the given keys under their shard's write lock.
+func (t *shardedTable[K1, K2, V]) Compute(k1 K1, k2 K2, fn func(oldVal V, loaded bool) (newVal V, del bool)) (actual V, ok bool) {
+	return t.shardFor(k1, k2).Compute(k1, k2, fn)
+}
+
+// LoadOrCompute returns the existing value for the given keys if one is
+// visible, otherwise it stores and returns the result of fn.
+func (t *shardedTable[K1, K2, V]) LoadOrCompute(k1 K1, k2 K2, fn func() V) (value V, loaded bool) {
+	return t.shardFor(k1, k2).LoadOrCompute(k1, k2, fn)
+}
+
+// Swap stores val as the new value for the given keys and returns the value
+// it replaced, if any.
+func (t *shardedTable[K1, K2, V]) Swap(k1 K1, k2 K2, val V) (previous V, loaded bool) {
+	return t.shardFor(k1, k2).Swap(k1, k2, val)
+}
+
+// Len returns the number of entries currently visible in the table
+func (t *shardedTable[K1, K2, V]) Len() int {
+	count := 0
+	for _, shard := range t.shards {
+		count += shard.Len()
+	}
+	return count
+}
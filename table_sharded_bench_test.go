@@ -0,0 +1,36 @@
+package structures
+
+import (
+	"strconv"
+	"testing"
+)
+
+// benchmarkTableMixed hammers t with a mix of reads and writes from multiple
+// goroutines, the workload NewShardedTable is meant to help with over NewTable.
+func benchmarkTableMixed(b *testing.B, t Table[string, string, int]) {
+	for i := 0; i < 1024; i++ {
+		_ = t.Set(strconv.Itoa(i), "x", i)
+	}
+
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			key := strconv.Itoa(i % 1024)
+			if i%10 == 0 {
+				_ = t.Set(key, "x", i)
+			} else {
+				t.Get(key, "x")
+			}
+			i++
+		}
+	})
+}
+
+func BenchmarkTable_SingleMutex(b *testing.B) {
+	benchmarkTableMixed(b, NewTable[string, string, int]())
+}
+
+func BenchmarkTable_Sharded(b *testing.B) {
+	benchmarkTableMixed(b, NewShardedTable[string, string, int](DefaultShardCount()))
+}